@@ -0,0 +1,338 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// AggSum sums the attribute values.
+	AggSum AggFunc = "sum"
+
+	// AggAvg calculates the average of the attribute values.
+	AggAvg AggFunc = "avg"
+
+	// AggMin selects the minimum attribute value.
+	AggMin AggFunc = "min"
+
+	// AggMax selects the maximum attribute value.
+	AggMax AggFunc = "max"
+
+	// AggMedian calculates the median of the attribute values.
+	AggMedian AggFunc = "median"
+)
+
+const (
+	// sqlAttrNumericValue extracts the attribute values which are numeric.
+	// Values are saved as JSON so numbers can be either JSON numbers or strings,
+	// and the values that are not numeric are selected as NULL.
+	sqlAttrNumericValue = `
+		CASE WHEN attribute.value #>> '{}' ~ '^-?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$'
+		THEN (attribute.value #>> '{}')::numeric END
+	`
+
+	tplAggregateAttrSQL = `
+		SELECT
+			COUNT(*) FILTER (WHERE attribute.value #>> '{}' IS NOT NULL AND (%[1]s) IS NULL),
+			%[2]s
+		FROM %[3]s
+			INNER JOIN event ON attribute.event_id = event.id
+			INNER JOIN tx ON event.tx_hash = tx.hash
+		WHERE event.type = $1 AND attribute.name = $2 AND tx.height BETWEEN $3 AND $4
+	`
+
+	// tplDailyTXCountsSQL counts the transactions of each day within a time range.
+	// The days are generated so the days without transactions have a zero count.
+	tplDailyTXCountsSQL = `
+		SELECT to_char(day, 'YYYY-MM-DD'), COUNT(tx.hash)
+		FROM generate_series(date_trunc('day', $1::timestamp), date_trunc('day', $2::timestamp), interval '1 day') AS day
+			LEFT JOIN tx ON %s
+		GROUP BY day
+		ORDER BY day
+	`
+	sqlDailyTXCountsTimeCond = `
+		tx.block_time BETWEEN $1 AND $2
+		AND tx.block_time >= day AND tx.block_time < day + interval '1 day'
+	`
+	sqlTXCountHistogram = `
+		SELECT bucket * $3, bucket * $3 + $3 - 1, COUNT(tx.height)
+		FROM generate_series($1::bigint / $3::bigint, $2::bigint / $3::bigint) AS bucket
+			LEFT JOIN tx ON tx.height BETWEEN $1 AND $2
+				AND tx.height BETWEEN bucket * $3 AND bucket * $3 + $3 - 1
+		GROUP BY bucket
+		ORDER BY bucket
+	`
+	tplTopAttrValuesSQL = `
+		SELECT attribute.value, COUNT(*)
+		FROM %s
+			INNER JOIN event ON attribute.event_id = event.id
+		WHERE event.type = $1 AND attribute.name = $2
+		GROUP BY attribute.value
+		ORDER BY COUNT(*) DESC, attribute.value
+		LIMIT $3
+	`
+	sqlDailyTXCountsEpochTimeCond = `
+		tx.block_time_ms BETWEEN $3 AND $4
+		AND tx.block_time_ms >= extract(epoch FROM day) * 1000
+		AND tx.block_time_ms < extract(epoch FROM day + interval '1 day') * 1000
+	`
+)
+
+var (
+	// ErrInvalidAggFunc is returned when an aggregate function is not supported.
+	ErrInvalidAggFunc = errors.New("invalid aggregate function")
+
+	// ErrNonNumericValue is returned when non numeric attribute values are aggregated.
+	ErrNonNumericValue = errors.New("non numeric attribute value")
+
+	// ErrInvalidBucketSize is returned when the size of the histogram buckets is not valid.
+	ErrInvalidBucketSize = errors.New("invalid bucket size")
+)
+
+// HeightBucket contains the number of transactions within a block height range.
+type HeightBucket struct {
+	// From is the first block height of the bucket.
+	From int64
+
+	// To is the last block height of the bucket.
+	To int64
+
+	// Count is the number of transactions within the bucket.
+	Count int64
+}
+
+// ValueCount contains the number of times that an event attribute value is saved.
+type ValueCount struct {
+	// Value is the attribute value decoded by the attribute encoder.
+	Value []byte
+
+	// Count is the number of attributes with the value.
+	Count int64
+}
+
+// AggFunc defines an aggregate function for numeric event attribute values.
+type AggFunc string
+
+// AggregateAttribute aggregates the numeric values of an event attribute within a block height range.
+// The values are aggregated by the database, and an error is returned when any of the values is not
+// numeric. Zero is returned when there are no attribute values to aggregate.
+// The range includes both the "from" and "to" block heights.
+func (a Adapter) AggregateAttribute(ctx context.Context, eventType, name string, agg AggFunc, from, to int64) (value float64, err error) {
+	if err := validateHeightRange(from, to); err != nil {
+		return 0, err
+	}
+
+	aggSQL, err := formatAggFunc(agg)
+	if err != nil {
+		return 0, err
+	}
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		invalid int64
+		result  sql.NullFloat64
+	)
+
+	q := fmt.Sprintf(tplAggregateAttrSQL, sqlAttrNumericValue, aggSQL, a.attrTableSQL())
+	err = a.withRetry(ctx, func() error {
+		return db.QueryRowContext(ctx, q, eventType, name, from, to).Scan(&invalid, &result)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error aggregating attribute '%s.%s': %w", eventType, name, err)
+	}
+
+	if invalid > 0 {
+		return 0, fmt.Errorf("%w: attribute '%s.%s' has %d non numeric values", ErrNonNumericValue, eventType, name, invalid)
+	}
+
+	return result.Float64, nil
+}
+
+// formatAggFunc returns the SQL to aggregate the numeric attribute values.
+func formatAggFunc(agg AggFunc) (string, error) {
+	switch agg {
+	case AggSum, AggAvg, AggMin, AggMax:
+		return fmt.Sprintf("%s(%s)", agg, sqlAttrNumericValue), nil
+	case AggMedian:
+		return fmt.Sprintf("percentile_cont(0.5) WITHIN GROUP (ORDER BY %s)", sqlAttrNumericValue), nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrInvalidAggFunc, agg)
+}
+
+// DailyTXCounts returns the number of transactions of each day within a time range.
+// The counts are indexed by date using the "YYYY-MM-DD" format, and the days are
+// calculated using the block time in UTC. All the days within the range are returned,
+// including the ones without transactions. The range includes both the start and end times.
+func (a Adapter) DailyTXCounts(ctx context.Context, start, end time.Time) (counts map[string]int64, err error) {
+	if start.After(end) {
+		return nil, fmt.Errorf("%w: start time %s is after end time %s", ErrInvalidTimeRange, start, end)
+	}
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	// The times are in UTC because the block times don't have a time zone
+	start, end = start.UTC(), end.UTC()
+	args := []any{start, end}
+
+	cond := sqlDailyTXCountsTimeCond
+	if a.epochTime {
+		cond = sqlDailyTXCountsEpochTimeCond
+		args = append(args, start.UnixMilli(), end.UnixMilli())
+	}
+
+	err = a.withRetry(ctx, func() (err error) {
+		counts, err = fetchDailyTXCounts(ctx, db, fmt.Sprintf(tplDailyTXCountsSQL, cond), args...)
+		return err
+	})
+
+	return counts, err
+}
+
+// fetchDailyTXCounts selects the number of transactions of each day without retrying.
+func fetchDailyTXCounts(ctx context.Context, db querier, q string, args ...any) (map[string]int64, error) {
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error counting daily transactions: %w", err)
+	}
+
+	defer rows.Close()
+
+	counts := map[string]int64{}
+	for rows.Next() {
+		var (
+			day   string
+			count int64
+		)
+
+		if err := rows.Scan(&day, &count); err != nil {
+			return nil, err
+		}
+
+		counts[day] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// TXCountHistogram returns the number of transactions within a block height range grouped in buckets.
+// Each bucket contains "bucketSize" block heights and the buckets start at heights that are multiple
+// of the bucket size, so the first and last buckets can include heights outside of the range, but only
+// the transactions within the range are counted. Buckets are sorted by height and all the buckets are
+// returned, including the ones without transactions. The range includes both the "from" and "to" block heights.
+func (a Adapter) TXCountHistogram(ctx context.Context, from, to, bucketSize int64) (buckets []HeightBucket, err error) {
+	if err := validateHeightRange(from, to); err != nil {
+		return nil, err
+	}
+
+	if bucketSize <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidBucketSize, bucketSize)
+	}
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.withRetry(ctx, func() (err error) {
+		buckets, err = fetchTXCountHistogram(ctx, db, from, to, bucketSize)
+		return err
+	})
+
+	return buckets, err
+}
+
+// fetchTXCountHistogram selects the number of transactions of each height bucket without retrying.
+func fetchTXCountHistogram(ctx context.Context, db querier, from, to, bucketSize int64) (buckets []HeightBucket, err error) {
+	rows, err := db.QueryContext(ctx, sqlTXCountHistogram, from, to, bucketSize)
+	if err != nil {
+		return nil, fmt.Errorf("error counting transactions by height: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var b HeightBucket
+		if err := rows.Scan(&b.From, &b.To, &b.Count); err != nil {
+			return nil, err
+		}
+
+		buckets = append(buckets, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// TopAttributeValues returns the most frequent values of an event attribute.
+// The values are sorted by the number of times they are saved, from the most frequent
+// to the least frequent, and up to "n" values are returned. Values are decoded with the
+// attribute encoder so they are returned with the same values they had before being saved.
+func (a Adapter) TopAttributeValues(ctx context.Context, eventType, name string, n int) (values []ValueCount, err error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	q := fmt.Sprintf(tplTopAttrValuesSQL, a.attrTableSQL())
+	err = a.withRetry(ctx, func() (err error) {
+		values, err = fetchTopAttributeValues(ctx, db, a.getAttrEncoder(), q, eventType, name, n)
+		return err
+	})
+
+	return values, err
+}
+
+// fetchTopAttributeValues selects the most frequent values of an event attribute without retrying.
+func fetchTopAttributeValues(ctx context.Context, db querier, enc AttributeEncoder, q, eventType, name string, n int) (counts []ValueCount, err error) {
+	rows, err := db.QueryContext(ctx, q, eventType, name, n)
+	if err != nil {
+		return nil, fmt.Errorf("error counting attribute '%s.%s' values: %w", eventType, name, err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			vc        ValueCount
+			valueData []byte
+		)
+
+		if err := rows.Scan(&valueData, &vc.Count); err != nil {
+			return nil, err
+		}
+
+		if vc.Value, err = enc.Decode(valueData); err != nil {
+			return nil, fmt.Errorf("error decoding event attr '%s.%s': %w", eventType, name, err)
+		}
+
+		counts = append(counts, vc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,237 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateAttribute(t *testing.T) {
+	cases := []struct {
+		agg    AggFunc
+		aggSQL string
+	}{
+		{AggSum, fmt.Sprintf("sum(%s)", sqlAttrNumericValue)},
+		{AggAvg, fmt.Sprintf("avg(%s)", sqlAttrNumericValue)},
+		{AggMin, fmt.Sprintf("min(%s)", sqlAttrNumericValue)},
+		{AggMax, fmt.Sprintf("max(%s)", sqlAttrNumericValue)},
+		{AggMedian, fmt.Sprintf("percentile_cont(0.5) WITHIN GROUP (ORDER BY %s)", sqlAttrNumericValue)},
+	}
+
+	for _, tt := range cases {
+		t.Run(string(tt.agg), func(t *testing.T) {
+			// Arrange
+			db, mock := createMatchEqualSQLMock(t)
+			defer db.Close()
+
+			adapter := Adapter{db: db}
+
+			// Arrange: Database mock and expectations
+			mock.
+				ExpectQuery(fmt.Sprintf(tplAggregateAttrSQL, sqlAttrNumericValue, tt.aggSQL, "attribute")).
+				WithArgs("tx", "fee", int64(1), int64(10)).
+				WillReturnRows(sqlmock.NewRows([]string{"invalid", "result"}).AddRow(0, 42.5))
+
+			// Act
+			v, err := adapter.AggregateAttribute(context.Background(), "tx", "fee", tt.agg, 1, 10)
+
+			// Assert
+			require.NoError(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+			require.Equal(t, 42.5, v)
+		})
+	}
+}
+
+func TestAggregateAttributeNoValues(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+	aggSQL, err := formatAggFunc(AggSum)
+	require.NoError(t, err)
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(fmt.Sprintf(tplAggregateAttrSQL, sqlAttrNumericValue, aggSQL, "attribute")).
+		WithArgs("tx", "fee", int64(1), int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"invalid", "result"}).AddRow(0, nil))
+
+	// Act
+	v, err := adapter.AggregateAttribute(context.Background(), "tx", "fee", AggSum, 1, 10)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Zero(t, v)
+}
+
+func TestAggregateAttributeNonNumericValue(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+	aggSQL, err := formatAggFunc(AggSum)
+	require.NoError(t, err)
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(fmt.Sprintf(tplAggregateAttrSQL, sqlAttrNumericValue, aggSQL, "attribute")).
+		WithArgs("transfer", "recipient", int64(1), int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"invalid", "result"}).AddRow(2, nil))
+
+	// Act
+	_, err = adapter.AggregateAttribute(context.Background(), "transfer", "recipient", AggSum, 1, 10)
+
+	// Assert
+	require.ErrorIs(t, err, ErrNonNumericValue)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAggregateAttributeInvalidAggFunc(t *testing.T) {
+	_, err := Adapter{}.AggregateAttribute(context.Background(), "tx", "fee", AggFunc("count"), 1, 10)
+
+	require.ErrorIs(t, err, ErrInvalidAggFunc)
+}
+
+func TestDailyTXCounts(t *testing.T) {
+	start := time.Date(2022, time.December, 1, 12, 0, 0, 0, time.FixedZone("UTC+1", 3600))
+	end := start.Add(48 * time.Hour)
+
+	cases := []struct {
+		name    string
+		adapter Adapter
+		cond    string
+		args    []driver.Value
+	}{
+		{
+			name: "block time",
+			cond: sqlDailyTXCountsTimeCond,
+			args: []driver.Value{start.UTC(), end.UTC()},
+		},
+		{
+			name:    "epoch time",
+			adapter: Adapter{saveOptions: saveOptions{epochTime: true}},
+			cond:    sqlDailyTXCountsEpochTimeCond,
+			args:    []driver.Value{start.UTC(), end.UTC(), start.UnixMilli(), end.UnixMilli()},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			db, mock := createMatchEqualSQLMock(t)
+			defer db.Close()
+
+			adapter := tt.adapter
+			adapter.db = db
+
+			// Arrange: Database mock and expectations
+			mock.
+				ExpectQuery(fmt.Sprintf(tplDailyTXCountsSQL, tt.cond)).
+				WithArgs(tt.args...).
+				WillReturnRows(
+					sqlmock.
+						NewRows([]string{"day", "count"}).
+						AddRow("2022-12-01", 5).
+						AddRow("2022-12-02", 0).
+						AddRow("2022-12-03", 2),
+				)
+
+			// Act
+			counts, err := adapter.DailyTXCounts(context.Background(), start, end)
+
+			// Assert
+			require.NoError(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+			require.Equal(t, map[string]int64{
+				"2022-12-01": 5,
+				"2022-12-02": 0,
+				"2022-12-03": 2,
+			}, counts)
+		})
+	}
+}
+
+func TestDailyTXCountsInvalidRange(t *testing.T) {
+	end := time.Now()
+
+	_, err := Adapter{}.DailyTXCounts(context.Background(), end.Add(time.Hour), end)
+
+	require.ErrorIs(t, err, ErrInvalidTimeRange)
+}
+
+func TestTXCountHistogram(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlTXCountHistogram).
+		WithArgs(int64(5), int64(25), int64(10)).
+		WillReturnRows(
+			sqlmock.
+				NewRows([]string{"from", "to", "count"}).
+				AddRow(0, 9, 3).
+				AddRow(10, 19, 0).
+				AddRow(20, 29, 7),
+		)
+
+	// Act
+	buckets, err := adapter.TXCountHistogram(context.Background(), 5, 25, 10)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, []HeightBucket{
+		{From: 0, To: 9, Count: 3},
+		{From: 10, To: 19},
+		{From: 20, To: 29, Count: 7},
+	}, buckets)
+}
+
+func TestTXCountHistogramInvalidBucketSize(t *testing.T) {
+	_, err := Adapter{}.TXCountHistogram(context.Background(), 1, 10, 0)
+
+	require.ErrorIs(t, err, ErrInvalidBucketSize)
+}
+
+func TestTopAttributeValues(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(fmt.Sprintf(tplTopAttrValuesSQL, "attribute")).
+		WithArgs("transfer", "recipient", 2).
+		WillReturnRows(
+			sqlmock.
+				NewRows([]string{"value", "count"}).
+				AddRow([]byte(`"cosmos1a"`), 5).
+				AddRow([]byte(`"cosmos1b"`), 3),
+		)
+
+	// Act
+	counts, err := adapter.TopAttributeValues(context.Background(), "transfer", "recipient", 2)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, []ValueCount{
+		{Value: []byte("cosmos1a"), Count: 5},
+		{Value: []byte("cosmos1b"), Count: 3},
+	}, counts)
+}
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+const tplAnalyzeSQL = `
+	ANALYZE %s
+`
+
+// analyzeTables contains the names of the tables analyzed after large saves.
+var analyzeTables = []string{"tx", "event", "attribute"}
+
+// WithAutoAnalyze analyzes the tables in the background after saves that insert more rows than the threshold.
+func WithAutoAnalyze(threshold int) Option {
+	return func(a *Adapter) {
+		a.autoAnalyzeThreshold = threshold
+	}
+}
+
+// analyzer runs the analysis of the tables in the background.
+type analyzer struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	running bool
+	closed  bool
+}
+
+func newAnalyzer() *analyzer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &analyzer{ctx: ctx, cancel: cancel}
+}
+
+// start runs a function in the background unless a previous one is still running.
+func (z *analyzer) start(fn func(context.Context)) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	if z.running || z.closed {
+		return
+	}
+
+	z.running = true
+	z.wg.Add(1)
+
+	go func() {
+		defer z.wg.Done()
+
+		fn(z.ctx)
+
+		z.mu.Lock()
+		z.running = false
+		z.mu.Unlock()
+	}()
+}
+
+// close cancels the running analysis and waits until it stops.
+func (z *analyzer) close() {
+	z.mu.Lock()
+	z.closed = true
+	z.mu.Unlock()
+
+	z.cancel()
+	z.wg.Wait()
+}
+
+// autoAnalyze starts analyzing the tables when a save inserted more rows than the threshold.
+func (a Adapter) autoAnalyze(txs []cosmosclient.TX, r SaveResult) {
+	if a.analyzer == nil || a.autoAnalyzeThreshold <= 0 || len(r.TXIDs) == 0 {
+		return
+	}
+
+	// Only the transactions before the first error are saved,
+	// except the ones that were skipped because of an error
+	skipped := make(map[int]struct{}, len(r.Errors))
+	for _, e := range r.Errors {
+		skipped[e.Index] = struct{}{}
+	}
+
+	n := len(r.TXIDs) + len(r.Errors)
+	if n > len(txs) {
+		n = len(txs)
+	}
+
+	var rows int
+	for i, tx := range txs[:n] {
+		if _, ok := skipped[i]; !ok {
+			rows += a.countRows(tx)
+		}
+	}
+
+	if rows > a.autoAnalyzeThreshold {
+		a.analyzer.start(a.analyze)
+	}
+}
+
+// countRows returns the number of rows inserted to save a transaction.
+func (a Adapter) countRows(tx cosmosclient.TX) int {
+	if tx.Raw == nil {
+		return 0
+	}
+
+	rows := 1
+	for _, e := range tx.Raw.TxResult.Events {
+		if !a.isEventAllowed(e.Type) {
+			continue
+		}
+
+		rows++
+
+		for _, attr := range e.Attributes {
+			if a.isAttrAllowed(string(attr.Key)) {
+				rows++
+			}
+		}
+	}
+
+	return rows
+}
+
+// analyze analyzes the tables using a separate database connection.
+func (a Adapter) analyze(ctx context.Context) {
+	if err := a.analyzeTables(ctx); err != nil && ctx.Err() == nil {
+		a.recordStatus(err)
+	}
+}
+
+func (a Adapter) analyzeTables(ctx context.Context) error {
+	db, err := a.getDB()
+	if err != nil {
+		return err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to analyze tables: %w", err)
+	}
+
+	defer conn.Close()
+
+	for _, name := range analyzeTables {
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf(tplAnalyzeSQL, name)); err != nil {
+			return fmt.Errorf("failed to analyze table '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
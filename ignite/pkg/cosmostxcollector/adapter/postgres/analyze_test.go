@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+func TestSaveWithAutoAnalyze(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := mustWith(t, Adapter{db: db, analyzer: newAnalyzer()}, WithAutoAnalyze(2))
+	defer adapter.analyzer.close()
+
+	// Arrange: Database mock and expectations
+	expectSaveTX(mock, true)
+
+	for _, name := range analyzeTables {
+		mock.ExpectExec(fmt.Sprintf(tplAnalyzeSQL, name)).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	// Act: The transaction, its event and the event attribute are three rows
+	err := adapter.Save(context.Background(), []cosmosclient.TX{createTestTX(t)})
+
+	// Assert: The tables are analyzed in the background
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return mock.ExpectationsWereMet() == nil
+	}, time.Second, time.Millisecond)
+}
+
+func TestSaveWithAutoAnalyzeBelowThreshold(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := mustWith(t, Adapter{db: db, analyzer: newAnalyzer()}, WithAutoAnalyze(3))
+
+	// Arrange: Database mock and expectations
+	expectSaveTX(mock, true)
+
+	// Act
+	err := adapter.Save(context.Background(), []cosmosclient.TX{createTestTX(t)})
+	adapter.analyzer.close()
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnalyzeError(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	wantErr := errors.New("analyze failed")
+	adapter := Adapter{db: db, status: &status{}}
+
+	// Arrange: Database mock and expectations
+	mock.ExpectExec(fmt.Sprintf(tplAnalyzeSQL, analyzeTables[0])).WillReturnError(wantErr)
+
+	// Act
+	adapter.analyze(context.Background())
+
+	// Assert
+	_, err := adapter.LastError()
+
+	require.ErrorIs(t, err, wantErr)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountRows(t *testing.T) {
+	// Arrange
+	tx := createTestTX(t)
+	adapter := mustWith(t, Adapter{}, WithEventTypeAllowlist("message"))
+
+	// Act
+	rows := adapter.countRows(tx)
+
+	// Assert: Only the transaction is saved when its events are not allowed
+	require.Equal(t, 1, rows)
+	require.Equal(t, 3, Adapter{}.countRows(tx))
+}
@@ -0,0 +1,619 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+const (
+	sqlSelectTXAttrs = `
+		SELECT event.index, event.type, attribute.name, attribute.value
+		FROM event INNER JOIN attribute ON event.id = attribute.event_id
+		WHERE event.tx_hash = $1
+		ORDER BY event.index, attribute.name
+	`
+	sqlSelectAttrsByHeightRange = `
+		SELECT tx.hash, tx.height, event.type, attribute.name, attribute.value
+		FROM tx
+			INNER JOIN event ON tx.hash = event.tx_hash
+			INNER JOIN attribute ON event.id = attribute.event_id
+		WHERE tx.height BETWEEN $1 AND $2
+		ORDER BY tx.height, tx.index, event.index, attribute.name
+	`
+	sqlSelectTXAttrsPage = `
+		SELECT tx.hash, tx.height, event.type, attribute.name, attribute.value
+		FROM tx
+			INNER JOIN event ON tx.hash = event.tx_hash
+			INNER JOIN attribute ON event.id = attribute.event_id
+		WHERE tx.hash = $1
+		ORDER BY event.index, attribute.index, attribute.name
+		LIMIT $2 OFFSET $3
+	`
+	sqlSelectAttrsBatch = `
+		SELECT event_id, name, value
+		FROM attribute
+		WHERE (event_id, name) > ($1, $2)
+		ORDER BY event_id, name
+		LIMIT $3
+		FOR UPDATE
+	`
+	sqlUpdateAttrValue = `
+		UPDATE attribute SET value = $3, value_type = COALESCE($4, value_type)
+		WHERE event_id = $1 AND name = $2
+	`
+
+	sqlAttrSavepoint         = "SAVEPOINT attribute"
+	sqlAttrRollbackSavepoint = "ROLLBACK TO SAVEPOINT attribute"
+	sqlAttrReleaseSavepoint  = "RELEASE SAVEPOINT attribute"
+)
+
+// Attribute defines a saved event attribute.
+type Attribute struct {
+	// TXHash is the hash of the transaction of the event.
+	TXHash string
+
+	// Height is the block height of the transaction.
+	Height int64
+
+	// EventType is the type of the event.
+	EventType string
+
+	// Name is the name of the attribute.
+	Name string
+
+	// Value is the attribute value decoded by the attribute encoder.
+	Value []byte
+}
+
+// AttributeEncoder defines an interface to encode event attribute values.
+// Attribute values are saved as JSON so encoded values must be valid JSON.
+type AttributeEncoder interface {
+	// Encode encodes an event attribute value to be saved.
+	Encode(value []byte) ([]byte, error)
+
+	// Decode decodes a saved event attribute value.
+	Decode(data []byte) ([]byte, error)
+}
+
+// WithAttributeEncoder configures the encoder for the event attribute values.
+func WithAttributeEncoder(enc AttributeEncoder) Option {
+	return func(a *Adapter) {
+		a.attrEncoder = enc
+	}
+}
+
+// WithSkipBadAttributes saves transactions without the event attributes that can't be encoded or saved.
+func WithSkipBadAttributes() Option {
+	return func(a *Adapter) {
+		a.skipBadAttrs = true
+	}
+}
+
+// WithAttributesSkipped configures a function to call with the attributes skipped by a committed save.
+func WithAttributesSkipped(fn func(ctx context.Context, attrs []SkippedAttribute)) Option {
+	return func(a *Adapter) {
+		a.attrsSkipped = fn
+	}
+}
+
+// SkippedAttribute contains an event attribute that was not saved because of an error.
+type SkippedAttribute struct {
+	// TXHash is the hash of the transaction of the attribute.
+	TXHash string
+
+	// EventType is the type of the event of the attribute.
+	EventType string
+
+	// Name is the name of the attribute.
+	Name string
+
+	// Err is the reason why the attribute was not saved.
+	Err error
+}
+
+// runAttributesSkipped calls the attributes skipped function when attributes were skipped.
+func (a Adapter) runAttributesSkipped(ctx context.Context, attrs []SkippedAttribute) {
+	if a.attrsSkipped != nil && len(attrs) > 0 {
+		a.attrsSkipped(ctx, attrs)
+	}
+}
+
+// WithAttributeAllowlist configures the names of the event attributes to save.
+func WithAttributeAllowlist(names ...string) Option {
+	return func(a *Adapter) {
+		a.attrAllowlist = make(map[string]struct{}, len(names))
+		for _, n := range names {
+			a.attrAllowlist[n] = struct{}{}
+		}
+	}
+}
+
+// WithEventTypeAllowlist configures the types of the transaction events to save.
+func WithEventTypeAllowlist(types ...string) Option {
+	return func(a *Adapter) {
+		a.eventAllowlist = make(map[string]struct{}, len(types))
+		for _, t := range types {
+			a.eventAllowlist[t] = struct{}{}
+		}
+	}
+}
+
+// JSONAttributeEncoder encodes event attribute values as JSON values.
+// Attribute values that are valid JSON are saved without changes
+// and any other value is saved as a JSON string.
+type JSONAttributeEncoder struct{}
+
+// Encode encodes an event attribute value as a JSON value.
+func (JSONAttributeEncoder) Encode(value []byte) ([]byte, error) {
+	if json.Valid(value) {
+		return value, nil
+	}
+
+	return json.Marshal(string(value))
+}
+
+// Decode decodes a JSON event attribute value.
+// JSON strings are decoded while other JSON values are returned unchanged.
+func (JSONAttributeEncoder) Decode(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] != '"' {
+		return data, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+
+	return []byte(s), nil
+}
+
+// Base64AttributeEncoder encodes event attribute values as base64 JSON strings.
+// It allows saving binary values without changing them.
+type Base64AttributeEncoder struct{}
+
+// Encode encodes an event attribute value as a base64 JSON string.
+func (Base64AttributeEncoder) Encode(value []byte) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Decode decodes a base64 JSON string event attribute value.
+func (Base64AttributeEncoder) Decode(data []byte) (value []byte, err error) {
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+// GetTXAttributes returns the events of a transaction with their attribute values.
+// The attribute values are decoded with the attribute encoder so they are returned
+// with the same values they had before being saved. Events are sorted by index and
+// their attributes by name. Events without attributes are not returned.
+func (a Adapter) GetTXAttributes(ctx context.Context, hash string) (events []cosmosclient.TXEvent, err error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.withRetry(ctx, func() (err error) {
+		events, err = fetchTXAttributes(ctx, db, a.getAttrEncoder(), hash)
+		return err
+	})
+
+	return events, err
+}
+
+// fetchTXAttributes selects the events of a transaction with their attribute values without retrying.
+func fetchTXAttributes(ctx context.Context, db querier, enc AttributeEncoder, hash string) (events []cosmosclient.TXEvent, err error) {
+	rows, err := db.QueryContext(ctx, sqlSelectTXAttrs, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	prev := -1
+
+	for rows.Next() {
+		var (
+			index     int
+			evtType   string
+			attr      cosmosclient.TXEventAttribute
+			valueData []byte
+		)
+
+		if err := rows.Scan(&index, &evtType, &attr.Key, &valueData); err != nil {
+			return nil, err
+		}
+
+		if attr.Value, err = enc.Decode(valueData); err != nil {
+			return nil, fmt.Errorf("error decoding event attr '%s.%s': %w", evtType, attr.Key, err)
+		}
+
+		// Rows are sorted by event so a new event starts when the index changes
+		if index != prev {
+			events = append(events, cosmosclient.TXEvent{Type: evtType})
+			prev = index
+		}
+
+		evt := &events[len(events)-1]
+		evt.Attributes = append(evt.Attributes, attr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetTXAttributesPage returns a page of the event attributes of a transaction.
+// It allows reading the attributes of transactions with many events without reading all of
+// them at once. Attributes are sorted by event index and by the index of the attribute within
+// its event. The attributes saved before their index was saved are sorted by name after the
+// ones with an index. The attribute values are decoded with the attribute encoder so they are
+// returned with the same values they had before being saved. No attributes are returned when
+// the limit is not greater than zero.
+func (a Adapter) GetTXAttributesPage(ctx context.Context, hash string, limit, offset int) (attrs []Attribute, err error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	err = a.withRetry(ctx, func() (err error) {
+		attrs, err = fetchTXAttributesPage(ctx, db, a.getAttrEncoder(), hash, limit, offset)
+		return err
+	})
+
+	return attrs, err
+}
+
+// fetchTXAttributesPage selects a page of the event attributes of a transaction without retrying.
+func fetchTXAttributesPage(ctx context.Context, db querier, enc AttributeEncoder, hash string, limit, offset int) (attrs []Attribute, err error) {
+	rows, err := db.QueryContext(ctx, sqlSelectTXAttrsPage, hash, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			attr      Attribute
+			valueData []byte
+		)
+
+		if err := rows.Scan(&attr.TXHash, &attr.Height, &attr.EventType, &attr.Name, &valueData); err != nil {
+			return nil, err
+		}
+
+		if attr.Value, err = enc.Decode(valueData); err != nil {
+			return nil, fmt.Errorf("error decoding event attr '%s.%s': %w", attr.EventType, attr.Name, err)
+		}
+
+		attrs = append(attrs, attr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attrs, nil
+}
+
+// IterateAttributes calls a function for each event attribute within a block height range.
+// Attributes are streamed from the database sorted by block height, transaction index, event
+// index and attribute name, so they are never kept in memory. Iteration stops when the function
+// returns an error, and the error is returned. The range includes both "from" and "to" heights.
+func (a Adapter) IterateAttributes(ctx context.Context, from, to int64, fn func(Attribute) error) (err error) {
+	if err := validateHeightRange(from, to); err != nil {
+		return err
+	}
+
+	defer func() { a.recordStatus(err) }()
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return err
+	}
+
+	// Only the query is retried because the function can't be called again for the same attributes
+	var rows *sql.Rows
+
+	err = a.withRetry(ctx, func() (err error) {
+		rows, err = db.QueryContext(ctx, sqlSelectAttrsByHeightRange, from, to)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	enc := a.getAttrEncoder()
+	for rows.Next() {
+		var (
+			attr      Attribute
+			valueData []byte
+		)
+
+		if err := rows.Scan(&attr.TXHash, &attr.Height, &attr.EventType, &attr.Name, &valueData); err != nil {
+			return err
+		}
+
+		if attr.Value, err = enc.Decode(valueData); err != nil {
+			return fmt.Errorf("error decoding event attr '%s.%s': %w", attr.EventType, attr.Name, err)
+		}
+
+		if err := fn(attr); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// ReencodeAttributes changes the encoding of all saved event attribute values.
+// Values are decoded with the encoder that was used to save them and are saved
+// again using the new encoder. Attributes are updated in batches, and each batch
+// is updated within a single database transaction. The batch size can be changed
+// using the WithBatchSize option. It returns the number of updated attributes.
+func (a Adapter) ReencodeAttributes(ctx context.Context, from, to AttributeEncoder) (count int64, err error) {
+	defer func() { a.recordStatus(err) }()
+
+	db, err := a.getDB()
+	if err != nil {
+		return 0, err
+	}
+
+	var lastKey attributeKey
+
+	for {
+		n, key, err := a.reencodeAttributesBatch(ctx, db, lastKey, from, to)
+		if err != nil {
+			return count, err
+		}
+
+		count += n
+
+		// The last batch is reached when there are no more attributes to update
+		if n < int64(a.getBatchSize()) {
+			return count, nil
+		}
+
+		lastKey = key
+	}
+}
+
+// attributeKey defines the primary key of an event attribute.
+type attributeKey struct {
+	eventID int64
+	name    string
+}
+
+// reencodeAttributesBatch changes the encoding of a batch of attributes that follow an attribute.
+// It returns the number of updated attributes and the key of the last updated attribute.
+func (a Adapter) reencodeAttributesBatch(ctx context.Context, db *sql.DB, after attributeKey, from, to AttributeEncoder) (count int64, last attributeKey, err error) {
+	sqlTx, err := db.BeginTx(ctx, a.txOptions())
+	if err != nil {
+		return 0, last, err
+	}
+
+	// Rollback is only called when the transaction is not committed
+	var committed bool
+	defer rollback(sqlTx, &committed)
+
+	rows, err := sqlTx.QueryContext(ctx, sqlSelectAttrsBatch, after.eventID, after.name, a.getBatchSize())
+	if err != nil {
+		return 0, last, err
+	}
+
+	// Values are read before updating them because the rows
+	// must be closed before executing other statements
+	type attrValue struct {
+		key  attributeKey
+		data []byte
+	}
+
+	var values []attrValue
+	for rows.Next() {
+		var v attrValue
+		if err := rows.Scan(&v.key.eventID, &v.key.name, &v.data); err != nil {
+			rows.Close()
+			return 0, last, err
+		}
+
+		values = append(values, v)
+	}
+
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return 0, last, err
+	}
+
+	for _, v := range values {
+		value, err := from.Decode(v.data)
+		if err != nil {
+			return 0, last, fmt.Errorf("error decoding event attr '%s' of event %d: %w", v.key.name, v.key.eventID, err)
+		}
+
+		data, err := to.Encode(value)
+		if err != nil {
+			return 0, last, fmt.Errorf("error encoding event attr '%s' of event %d: %w", v.key.name, v.key.eventID, err)
+		}
+
+		// The value type is only updated when type hints are enabled
+		var valueType sql.NullString
+		if a.typeHints {
+			valueType = sql.NullString{String: getValueType(data), Valid: true}
+		}
+
+		if _, err := sqlTx.ExecContext(ctx, sqlUpdateAttrValue, v.key.eventID, v.key.name, data, valueType); err != nil {
+			return 0, last, fmt.Errorf("error updating event attr '%s' of event %d: %w", v.key.name, v.key.eventID, err)
+		}
+
+		last = v.key
+	}
+
+	// The transaction is done after calling commit even when it fails
+	committed = true
+
+	if err := commit(sqlTx); err != nil {
+		return 0, last, err
+	}
+
+	return int64(len(values)), last, nil
+}
+
+// txEvent is a transaction event to save.
+type txEvent struct {
+	cosmosclient.TXEvent
+
+	// Index is the position of the event within the events of the transaction result.
+	Index int
+
+	// AttrIndexes contains the position of each attribute within the attributes of the event.
+	AttrIndexes []int
+}
+
+// getEvents returns the transaction events with the attribute values encoded by the attribute encoder.
+// The events keep the index they have within the transaction result, even when some events are dropped.
+// The attributes that can't be encoded are returned as skipped when bad attributes are skipped.
+func (a Adapter) getEvents(tx cosmosclient.TX) (events []txEvent, skipped []SkippedAttribute, err error) {
+	enc := a.getAttrEncoder()
+	for i, e := range tx.Raw.TxResult.Events {
+		if !a.isEventAllowed(e.Type) {
+			continue
+		}
+
+		evt := txEvent{
+			TXEvent: cosmosclient.TXEvent{Type: e.Type},
+			Index:   i,
+		}
+
+		for j, attr := range e.Attributes {
+			if !a.isAttrAllowed(string(attr.Key)) {
+				continue
+			}
+
+			v, err := enc.Encode(attr.Value)
+			if err != nil {
+				if a.skipBadAttrs {
+					skipped = append(skipped, SkippedAttribute{
+						TXHash:    tx.Raw.Hash.String(),
+						EventType: e.Type,
+						Name:      string(attr.Key),
+						Err:       fmt.Errorf("error encoding event attr: %w", err),
+					})
+					continue
+				}
+
+				return nil, nil, fmt.Errorf("error encoding event attr '%s.%s': %w", e.Type, attr.Key, err)
+			}
+
+			evt.Attributes = append(evt.Attributes, cosmosclient.TXEventAttribute{
+				Key:   string(attr.Key),
+				Value: v,
+			})
+			evt.AttrIndexes = append(evt.AttrIndexes, j)
+		}
+
+		events = append(events, evt)
+	}
+
+	return events, skipped, nil
+}
+
+// isEventAllowed checks if a transaction event must be saved.
+func (a Adapter) isEventAllowed(eventType string) bool {
+	if a.eventAllowlist == nil {
+		return true
+	}
+
+	_, ok := a.eventAllowlist[eventType]
+	return ok
+}
+
+// isAttrAllowed checks if an event attribute must be saved.
+func (a Adapter) isAttrAllowed(name string) bool {
+	if a.attrAllowlist == nil {
+		return true
+	}
+
+	_, ok := a.attrAllowlist[name]
+	return ok
+}
+
+// getAttrStmt returns the statement to insert the event attributes.
+// The statement inserts each attribute within a savepoint when bad attributes are skipped.
+func (a Adapter) getAttrStmt(sqlTx *sql.Tx, s stmt) stmt {
+	if !a.skipBadAttrs {
+		return s
+	}
+
+	return savepointStmt{sqlTx, s}
+}
+
+func (a Adapter) getAttrEncoder() AttributeEncoder {
+	if a.attrEncoder == nil {
+		return JSONAttributeEncoder{}
+	}
+
+	return a.attrEncoder
+}
+
+// savepointStmt executes an SQL statement within a savepoint.
+// The changes done by the statement are rolled back when it fails,
+// which allows the database transaction to continue after the error.
+type savepointStmt struct {
+	tx   *sql.Tx
+	stmt stmt
+}
+
+func (s savepointStmt) ExecContext(ctx context.Context, args ...any) (sql.Result, error) {
+	if _, err := s.tx.ExecContext(ctx, sqlAttrSavepoint); err != nil {
+		return nil, savepointError{err}
+	}
+
+	res, err := s.stmt.ExecContext(ctx, args...)
+	if err != nil {
+		if _, rerr := s.tx.ExecContext(ctx, sqlAttrRollbackSavepoint); rerr != nil {
+			return nil, savepointError{rerr}
+		}
+
+		return nil, err
+	}
+
+	if _, err := s.tx.ExecContext(ctx, sqlAttrReleaseSavepoint); err != nil {
+		return nil, savepointError{err}
+	}
+
+	return res, nil
+}
+
+func (s savepointStmt) QueryRowContext(ctx context.Context, args ...any) *sql.Row {
+	return s.stmt.QueryRowContext(ctx, args...)
+}
+
+// savepointError is returned when an attribute savepoint command fails.
+type savepointError struct {
+	err error
+}
+
+func (e savepointError) Error() string {
+	return fmt.Sprintf("attribute savepoint failed: %v", e.err)
+}
+
+func (e savepointError) Unwrap() error {
+	return e.err
+}
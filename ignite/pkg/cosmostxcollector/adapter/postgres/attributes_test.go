@@ -0,0 +1,513 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+func TestAttributeEncoders(t *testing.T) {
+	cases := []struct {
+		name  string
+		enc   AttributeEncoder
+		value []byte
+		want  string
+	}{
+		{
+			name:  "json string",
+			enc:   JSONAttributeEncoder{},
+			value: []byte("cosmos1crje20aj4gxdtyct7z3knxqry2jqt2fuaey6u5"),
+			want:  `"cosmos1crje20aj4gxdtyct7z3knxqry2jqt2fuaey6u5"`,
+		},
+		{
+			name:  "json number",
+			enc:   JSONAttributeEncoder{},
+			value: []byte("100"),
+			want:  "100",
+		},
+		{
+			name:  "json object",
+			enc:   JSONAttributeEncoder{},
+			value: []byte(`{"amount":"100"}`),
+			want:  `{"amount":"100"}`,
+		},
+		{
+			name:  "base64",
+			enc:   Base64AttributeEncoder{},
+			value: []byte{0x00, 0xFF, 0x10},
+			want:  `"AP8Q"`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			data, err := tt.enc.Encode(tt.value)
+			require.NoError(t, err)
+
+			value, err := tt.enc.Decode(data)
+			require.NoError(t, err)
+
+			// Assert
+			require.Equal(t, tt.want, string(data))
+			require.Equal(t, tt.value, value)
+		})
+	}
+}
+
+func TestGetTXAttributes(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{attrOptions: attrOptions{attrEncoder: Base64AttributeEncoder{}}, db: db}
+	hash := "F2564C78071E26643AE9B3E2A19FA0DC10D4D9E873AA0BE808660123F11A1E78"
+	tx := createTestTX(t)
+	tx.Raw.TxResult.Events = append(tx.Raw.TxResult.Events, abci.Event{
+		Type: "message",
+		Attributes: []abci.EventAttribute{
+			{Key: []byte("data"), Value: []byte{0x00, 0xFF}},
+		},
+	})
+
+	// Arrange: Encode the values as they would be saved
+	events, _, err := adapter.getEvents(tx)
+	require.NoError(t, err)
+
+	rows := sqlmock.NewRows([]string{"index", "type", "name", "value"})
+	for _, evt := range events {
+		for _, attr := range evt.Attributes {
+			rows.AddRow(evt.Index, evt.Type, attr.Key, attr.Value)
+		}
+	}
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectTXAttrs).
+		WithArgs(hash).
+		WillReturnRows(rows)
+
+	// Act
+	got, err := adapter.GetTXAttributes(context.Background(), hash)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, []cosmosclient.TXEvent{
+		{
+			Type: "transfer",
+			Attributes: []cosmosclient.TXEventAttribute{
+				{Key: "recipient", Value: []byte("cosmos1crje20aj4gxdtyct7z3knxqry2jqt2fuaey6u5")},
+			},
+		},
+		{
+			Type: "message",
+			Attributes: []cosmosclient.TXEventAttribute{
+				{Key: "data", Value: []byte{0x00, 0xFF}},
+			},
+		},
+	}, got)
+}
+
+func TestIterateAttributes(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+	hash := "F2564C78071E26643AE9B3E2A19FA0DC10D4D9E873AA0BE808660123F11A1E78"
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectAttrsByHeightRange).
+		WithArgs(int64(1), int64(10)).
+		WillReturnRows(
+			sqlmock.
+				NewRows([]string{"hash", "height", "type", "name", "value"}).
+				AddRow(hash, 1, "transfer", "amount", []byte(`"100stake"`)).
+				AddRow(hash, 2, "message", "action", []byte(`"send"`)),
+		)
+
+	var attrs []Attribute
+
+	// Act
+	err := adapter.IterateAttributes(context.Background(), 1, 10, func(attr Attribute) error {
+		attrs = append(attrs, attr)
+		return nil
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, []Attribute{
+		{TXHash: hash, Height: 1, EventType: "transfer", Name: "amount", Value: []byte("100stake")},
+		{TXHash: hash, Height: 2, EventType: "message", Name: "action", Value: []byte("send")},
+	}, attrs)
+}
+
+func TestGetTXAttributesPage(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+	hash := "F2564C78071E26643AE9B3E2A19FA0DC10D4D9E873AA0BE808660123F11A1E78"
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectTXAttrsPage).
+		WithArgs(hash, 2, 4).
+		WillReturnRows(
+			sqlmock.
+				NewRows([]string{"hash", "height", "type", "name", "value"}).
+				AddRow(hash, 1, "transfer", "recipient", []byte(`"cosmos1"`)).
+				AddRow(hash, 1, "transfer", "amount", []byte(`{"denom":"stake"}`)),
+		)
+
+	// Act
+	attrs, err := adapter.GetTXAttributesPage(context.Background(), hash, 2, 4)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, []Attribute{
+		{TXHash: hash, Height: 1, EventType: "transfer", Name: "recipient", Value: []byte("cosmos1")},
+		{TXHash: hash, Height: 1, EventType: "transfer", Name: "amount", Value: []byte(`{"denom":"stake"}`)},
+	}, attrs)
+}
+
+func TestGetTXAttributesPageWithoutLimit(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+
+	// Act
+	attrs, err := adapter.GetTXAttributesPage(context.Background(), "A", 0, 0)
+
+	// Assert
+	require.NoError(t, err)
+	require.Empty(t, attrs)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIterateAttributesStop(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+	errStop := errors.New("stop")
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectAttrsByHeightRange).
+		WithArgs(int64(1), int64(10)).
+		WillReturnRows(
+			sqlmock.
+				NewRows([]string{"hash", "height", "type", "name", "value"}).
+				AddRow("A", 1, "transfer", "amount", []byte("1")).
+				AddRow("B", 2, "transfer", "amount", []byte("2")),
+		)
+
+	calls := 0
+
+	// Act
+	err := adapter.IterateAttributes(context.Background(), 1, 10, func(Attribute) error {
+		calls++
+		return errStop
+	})
+
+	// Assert
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, 1, calls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReencodeAttributes(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{saveOptions: saveOptions{batchSize: 2}, db: db}
+	attrFields := []string{"event_id", "name", "value"}
+	updateResult := sqlmock.NewResult(0, 1)
+
+	// Arrange: The first batch is full so a second batch is updated
+	mock.ExpectBegin()
+	mock.
+		ExpectQuery(sqlSelectAttrsBatch).
+		WithArgs(int64(0), "", 2).
+		WillReturnRows(
+			sqlmock.
+				NewRows(attrFields).
+				AddRow(1, "data", []byte(`"foo"`)).
+				AddRow(1, "sender", []byte(`"bar"`)),
+		)
+	mock.
+		ExpectExec(sqlUpdateAttrValue).
+		WithArgs(int64(1), "data", []byte(`"Zm9v"`), sql.NullString{}).
+		WillReturnResult(updateResult)
+	mock.
+		ExpectExec(sqlUpdateAttrValue).
+		WithArgs(int64(1), "sender", []byte(`"YmFy"`), sql.NullString{}).
+		WillReturnResult(updateResult)
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.
+		ExpectQuery(sqlSelectAttrsBatch).
+		WithArgs(int64(1), "sender", 2).
+		WillReturnRows(
+			sqlmock.
+				NewRows(attrFields).
+				AddRow(2, "data", []byte(`100`)),
+		)
+	mock.
+		ExpectExec(sqlUpdateAttrValue).
+		WithArgs(int64(2), "data", []byte(`"MTAw"`), sql.NullString{}).
+		WillReturnResult(updateResult)
+	mock.ExpectCommit()
+
+	// Act
+	count, err := adapter.ReencodeAttributes(context.Background(), JSONAttributeEncoder{}, Base64AttributeEncoder{})
+
+	// Assert
+	require.NoError(t, err)
+	require.EqualValues(t, 3, count)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveOneSkipBadAttributes(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	var skipped []SkippedAttribute
+
+	adapter := mustWith(t, Adapter{db: db},
+		WithSkipBadAttributes(),
+		WithAttributesSkipped(func(_ context.Context, attrs []SkippedAttribute) {
+			skipped = attrs
+		}),
+	)
+	tx := createTestTX(t)
+	tx.Raw.TxResult.Events[0].Attributes = append(tx.Raw.TxResult.Events[0].Attributes, abci.EventAttribute{
+		Key:   []byte("amount"),
+		Value: []byte("10stake"),
+	})
+
+	insertResult := sqlmock.NewResult(0, 1)
+
+	// Arrange: Database mock and expectations where the second attribute fails
+	mock.ExpectBegin()
+	mock.ExpectExec(sqlInsertRawTX).WillReturnResult(insertResult)
+	mock.ExpectQuery(sqlInsertTX).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(sqlInsertEvent).WillReturnRows(sqlmock.NewRows([]string{"event_id"}).AddRow(1))
+	mock.ExpectExec(sqlAttrSavepoint).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(sqlInsertEventAttr).WillReturnResult(insertResult)
+	mock.ExpectExec(sqlAttrReleaseSavepoint).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(sqlAttrSavepoint).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(sqlInsertEventAttr).WillReturnError(errors.New("invalid value"))
+	mock.ExpectExec(sqlAttrRollbackSavepoint).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// Act
+	err := adapter.SaveOne(context.Background(), tx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Len(t, skipped, 1)
+	require.Equal(t, "amount", skipped[0].Name)
+	require.EqualError(t, skipped[0].Err, "invalid value")
+}
+
+func TestSaveWithResultSkipBadAttributes(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := mustWith(t, Adapter{db: db},
+		WithAttributeEncoder(failingAttributeEncoder{}),
+		WithSkipBadAttributes(),
+	)
+	tx := createTestTX(t)
+	insertResult := sqlmock.NewResult(0, 1)
+
+	// Arrange: Database mock and expectations where the attribute is not inserted
+	mock.ExpectBegin()
+	mock.ExpectPrepare(sqlInsertTX)
+	mock.ExpectPrepare(sqlInsertEvent)
+	mock.ExpectPrepare(sqlInsertEventAttr)
+	mock.ExpectExec(sqlInsertRawTX).WillReturnResult(insertResult)
+	mock.ExpectQuery(sqlInsertTX).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(sqlInsertEvent).WillReturnRows(sqlmock.NewRows([]string{"event_id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	// Act
+	r, err := adapter.SaveWithResult(context.Background(), []cosmosclient.TX{tx})
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Len(t, r.SkippedAttributes, 1)
+	require.Equal(t, tx.Raw.Hash.String(), r.SkippedAttributes[0].TXHash)
+	require.Equal(t, "recipient", r.SkippedAttributes[0].Name)
+}
+
+func TestSaveOneSkipBadAttributesSavepointError(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := mustWith(t, Adapter{db: db}, WithSkipBadAttributes())
+	wantErr := errors.New("savepoint failed")
+
+	// Arrange: Database mock and expectations
+	mock.ExpectBegin()
+	mock.ExpectExec(sqlInsertRawTX).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(sqlInsertTX).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(sqlInsertEvent).WillReturnRows(sqlmock.NewRows([]string{"event_id"}).AddRow(1))
+	mock.ExpectExec(sqlAttrSavepoint).WillReturnError(wantErr)
+	mock.ExpectRollback()
+
+	// Act
+	err := adapter.SaveOne(context.Background(), createTestTX(t))
+
+	// Assert
+	require.ErrorIs(t, err, wantErr)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEventsSkipBadAttributes(t *testing.T) {
+	// Arrange
+	adapter := mustWith(t, Adapter{},
+		WithAttributeEncoder(failingAttributeEncoder{}),
+		WithSkipBadAttributes(),
+	)
+	tx := createTestTX(t)
+
+	// Act
+	events, skipped, err := adapter.getEvents(tx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, []txEvent{{TXEvent: cosmosclient.TXEvent{Type: "transfer"}}}, events)
+	require.Len(t, skipped, 1)
+	require.Equal(t, tx.Raw.Hash.String(), skipped[0].TXHash)
+	require.Equal(t, "transfer", skipped[0].EventType)
+	require.Equal(t, "recipient", skipped[0].Name)
+}
+
+func TestGetEventsWithAttributeAllowlist(t *testing.T) {
+	// Arrange
+	tx := createTestTX(t)
+	tx.Raw.TxResult.Events[0].Attributes = append(tx.Raw.TxResult.Events[0].Attributes, abci.EventAttribute{
+		Key:   []byte("amount"),
+		Value: []byte("42stake"),
+	})
+
+	adapter := mustWith(t, Adapter{}, WithAttributeAllowlist("amount"))
+	want := []txEvent{
+		{
+			TXEvent: cosmosclient.TXEvent{
+				Type: "transfer",
+				Attributes: []cosmosclient.TXEventAttribute{
+					{Key: "amount", Value: []byte(`"42stake"`)},
+				},
+			},
+			AttrIndexes: []int{1},
+		},
+	}
+
+	// Act
+	events, _, err := adapter.getEvents(tx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, want, events)
+}
+
+func TestGetEventsWithEmptyAttributeAllowlist(t *testing.T) {
+	// Arrange
+	adapter := mustWith(t, Adapter{}, WithAttributeAllowlist())
+	tx := createTestTX(t)
+
+	// Act
+	events, _, err := adapter.getEvents(tx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, []txEvent{{TXEvent: cosmosclient.TXEvent{Type: "transfer"}}}, events)
+}
+
+func TestGetEventsWithEventTypeAllowlist(t *testing.T) {
+	// Arrange
+	tx := createTestTX(t)
+	tx.Raw.TxResult.Events = append(
+		[]abci.Event{{Type: "message"}},
+		append(tx.Raw.TxResult.Events, abci.Event{Type: "message"})...,
+	)
+
+	adapter := mustWith(t, Adapter{}, WithEventTypeAllowlist("transfer"))
+
+	// Act
+	events, _, err := adapter.getEvents(tx)
+
+	// Assert: The event keeps its index within the transaction result
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "transfer", events[0].Type)
+	require.Equal(t, 1, events[0].Index)
+}
+
+func TestSaveWithEventTypeAllowlist(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	tx := createTestTX(t)
+	tx.Raw.TxResult.Events = append([]abci.Event{{Type: "message"}}, tx.Raw.TxResult.Events...)
+	adapter := mustWith(t, Adapter{db: db}, WithEventTypeAllowlist("transfer"))
+	hash := tx.Raw.Hash.String()
+
+	// Arrange: Database mock and expectations
+	mock.ExpectBegin()
+	txStmt := mock.ExpectPrepare(sqlInsertTX)
+	evtStmt := mock.ExpectPrepare(sqlInsertEvent)
+	attrStmt := mock.ExpectPrepare(sqlInsertEventAttr)
+	mock.ExpectExec(sqlInsertRawTX).WillReturnResult(sqlmock.NewResult(0, 1))
+	txStmt.ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	evtStmt.
+		ExpectQuery().
+		WithArgs(hash, "transfer", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id"}).AddRow(1))
+	attrStmt.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := adapter.Save(context.Background(), []cosmosclient.TX{tx})
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// failingAttributeEncoder is an attribute encoder that always fails.
+type failingAttributeEncoder struct{}
+
+func (failingAttributeEncoder) Encode([]byte) ([]byte, error) {
+	return nil, errors.New("encoding failed")
+}
+
+func (failingAttributeEncoder) Decode([]byte) ([]byte, error) {
+	return nil, errors.New("decoding failed")
+}
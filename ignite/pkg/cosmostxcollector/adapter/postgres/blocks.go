@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// MaxProposerBlocks defines the maximum number of blocks proposed by a validator that can be read at once.
+const MaxProposerBlocks = 1000
+
+const (
+	sqlSelectBlocksByProposer = `
+		SELECT height, tx_count, created_at
+		FROM block
+		WHERE proposer = $1
+		ORDER BY height DESC
+		LIMIT $2
+	`
+	sqlSelectMissingHeights = `
+		SELECT h.height
+		FROM generate_series($1::bigint, $2::bigint) AS h(height)
+		WHERE NOT EXISTS (SELECT 1 FROM tx WHERE tx.height = h.height)
+			AND NOT EXISTS (SELECT 1 FROM block WHERE block.height = h.height AND block.tx_count = 0)
+		ORDER BY h.height
+	`
+)
+
+// BlockSummary contains the information of a saved block.
+type BlockSummary struct {
+	// Height is the height of the block.
+	Height int64
+
+	// TXCount is the number of transactions saved for the block.
+	TXCount int
+
+	// Proposer is the address of the validator that proposed the block.
+	Proposer string
+
+	// SavedAt is the time when the block was saved.
+	SavedAt time.Time
+}
+
+// GetBlocksByProposer returns the latest saved blocks proposed by a validator.
+// The blocks are sorted by height in descending order and up to "limit" blocks
+// are returned. Blocks saved without a proposer are never returned.
+// The limit can't be greater than MaxProposerBlocks, and no blocks are
+// returned when the limit is not greater than zero.
+func (a Adapter) GetBlocksByProposer(ctx context.Context, proposer string, limit int) (blocks []BlockSummary, err error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	if limit > MaxProposerBlocks {
+		limit = MaxProposerBlocks
+	}
+
+	err = a.withRetry(ctx, func() (err error) {
+		blocks, err = fetchBlocksByProposer(ctx, db, proposer, limit)
+		return err
+	})
+
+	return blocks, err
+}
+
+// fetchBlocksByProposer selects the latest saved blocks proposed by a validator without retrying.
+func fetchBlocksByProposer(ctx context.Context, db querier, proposer string, limit int) (blocks []BlockSummary, err error) {
+	rows, err := db.QueryContext(ctx, sqlSelectBlocksByProposer, proposer, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error reading blocks proposed by '%s': %w", proposer, err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			b       = BlockSummary{Proposer: proposer}
+			savedAt sql.NullTime
+		)
+
+		if err := rows.Scan(&b.Height, &b.TXCount, &savedAt); err != nil {
+			return nil, err
+		}
+
+		b.SavedAt = savedAt.Time
+		blocks = append(blocks, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// MissingHeights returns the block heights within a range that don't have saved transactions.
+// Blocks can be empty, so heights without transactions are only returned when they are not
+// known to be empty. A block is known to be empty when it is saved with SaveBlock without
+// transactions, otherwise blocks are expected to have transactions. It allows finding the
+// block heights that must be indexed again. The range includes both the "from" and "to" heights.
+func (a Adapter) MissingHeights(ctx context.Context, from, to int64) (heights []int64, err error) {
+	if err := validateHeightRange(from, to); err != nil {
+		return nil, err
+	}
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.withRetry(ctx, func() (err error) {
+		heights, err = fetchMissingHeights(ctx, db, from, to)
+		return err
+	})
+
+	return heights, err
+}
+
+// fetchMissingHeights selects the block heights within a range that don't have saved transactions without retrying.
+func fetchMissingHeights(ctx context.Context, db querier, from, to int64) (heights []int64, err error) {
+	rows, err := db.QueryContext(ctx, sqlSelectMissingHeights, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read missing heights: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var h int64
+		if err := rows.Scan(&h); err != nil {
+			return nil, err
+		}
+
+		heights = append(heights, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return heights, nil
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBlocksByProposer(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+	proposer := "cosmosvalcons1crje20aj4gxdtyct7z3knxqry2jqt2fu5mx6lf"
+	savedAt := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectBlocksByProposer).
+		WithArgs(proposer, 2).
+		WillReturnRows(
+			sqlmock.
+				NewRows([]string{"height", "tx_count", "created_at"}).
+				AddRow(42, 3, savedAt).
+				AddRow(40, 0, nil),
+		)
+
+	// Act
+	blocks, err := adapter.GetBlocksByProposer(context.Background(), proposer, 2)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, []BlockSummary{
+		{Height: 42, TXCount: 3, Proposer: proposer, SavedAt: savedAt},
+		{Height: 40, Proposer: proposer},
+	}, blocks)
+}
+
+func TestGetBlocksByProposerLimit(t *testing.T) {
+	cases := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{
+			name:  "zero",
+			limit: 0,
+		},
+		{
+			name:  "negative",
+			limit: -1,
+		},
+		{
+			name:  "above maximum",
+			limit: MaxProposerBlocks + 1,
+			want:  MaxProposerBlocks,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			db, mock := createMatchEqualSQLMock(t)
+			defer db.Close()
+
+			adapter := Adapter{db: db}
+
+			// Arrange: The database is only queried when the limit is greater than zero
+			if tt.want > 0 {
+				mock.
+					ExpectQuery(sqlSelectBlocksByProposer).
+					WithArgs("foo", tt.want).
+					WillReturnRows(sqlmock.NewRows([]string{"height", "tx_count", "created_at"}))
+			}
+
+			// Act
+			blocks, err := adapter.GetBlocksByProposer(context.Background(), "foo", tt.limit)
+
+			// Assert
+			require.NoError(t, err)
+			require.Empty(t, blocks)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetBlocksByProposerClosed(t *testing.T) {
+	_, err := Adapter{}.GetBlocksByProposer(context.Background(), "foo", 1)
+
+	require.ErrorIs(t, err, ErrClosed)
+}
+
+func TestMissingHeights(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectMissingHeights).
+		WithArgs(int64(1), int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"height"}).AddRow(3).AddRow(7))
+
+	// Act
+	heights, err := adapter.MissingHeights(context.Background(), 1, 10)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, []int64{3, 7}, heights)
+}
+
+func TestMissingHeightsInvalidRange(t *testing.T) {
+	_, err := Adapter{}.MissingHeights(context.Background(), 10, 1)
+
+	require.ErrorIs(t, err, ErrInvalidHeightRange)
+}
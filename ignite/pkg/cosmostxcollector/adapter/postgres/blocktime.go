@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+// ErrMissingBlockTime is returned when a transaction without block time can't be saved.
+var ErrMissingBlockTime = errors.New("transaction block time is missing")
+
+// BlockTimeResolver defines a function to get the time of a block.
+type BlockTimeResolver func(ctx context.Context, height int64) (time.Time, error)
+
+// WithBlockTimeResolver configures a function to get the block time of the saved transactions that don't have it.
+func WithBlockTimeResolver(fn BlockTimeResolver) Option {
+	return func(a *Adapter) {
+		a.blockTimeResolver = fn
+	}
+}
+
+// WithRequireBlockTime configures the adapter to fail saving transactions without block time.
+func WithRequireBlockTime() Option {
+	return func(a *Adapter) {
+		a.requireBlockTime = true
+	}
+}
+
+// resolveBlockTime sets the block time of a transaction when it doesn't have it.
+func (a Adapter) resolveBlockTime(ctx context.Context, tx cosmosclient.TX) (cosmosclient.TX, error) {
+	if !tx.BlockTime.IsZero() {
+		return tx, nil
+	}
+
+	if a.blockTimeResolver != nil {
+		t, err := a.blockTimeResolver(ctx, tx.Raw.Height)
+		if err != nil {
+			return tx, fmt.Errorf("failed to resolve block time of height %d: %w", tx.Raw.Height, err)
+		}
+
+		tx.BlockTime = t
+	}
+
+	if a.requireBlockTime && tx.BlockTime.IsZero() {
+		return tx, fmt.Errorf("%w: TX %s", ErrMissingBlockTime, tx.Raw.Hash)
+	}
+
+	return tx, nil
+}
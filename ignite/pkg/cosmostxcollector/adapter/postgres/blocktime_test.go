@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveBlockTime(t *testing.T) {
+	// Arrange
+	blockTime := time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC)
+	tx := createTestTX(t)
+
+	var height int64
+
+	adapter := mustWith(t, Adapter{}, WithBlockTimeResolver(func(_ context.Context, h int64) (time.Time, error) {
+		height = h
+		return blockTime, nil
+	}))
+
+	// Act
+	got, err := adapter.resolveBlockTime(context.Background(), tx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, tx.Raw.Height, height)
+	require.Equal(t, blockTime, got.BlockTime)
+}
+
+func TestResolveBlockTimeError(t *testing.T) {
+	// Arrange
+	wantErr := errors.New("block not found")
+	adapter := mustWith(t, Adapter{}, WithBlockTimeResolver(func(context.Context, int64) (time.Time, error) {
+		return time.Time{}, wantErr
+	}))
+
+	// Act
+	_, err := adapter.resolveBlockTime(context.Background(), createTestTX(t))
+
+	// Assert
+	require.ErrorIs(t, err, wantErr)
+}
+
+func TestResolveBlockTimeRequired(t *testing.T) {
+	// Arrange
+	adapter := mustWith(t, Adapter{}, WithRequireBlockTime())
+
+	// Act
+	_, err := adapter.resolveBlockTime(context.Background(), createTestTX(t))
+
+	// Assert
+	require.ErrorIs(t, err, ErrMissingBlockTime)
+}
+
+func TestTXInsertArgsWithoutBlockTime(t *testing.T) {
+	cases := []struct {
+		name    string
+		adapter Adapter
+	}{
+		{
+			name:    "timestamp",
+			adapter: Adapter{},
+		},
+		{
+			name:    "epoch time",
+			adapter: Adapter{saveOptions: saveOptions{epochTime: true}},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			args := tt.adapter.txInsertArgs(createTestTX(t))
+
+			// Assert: The block time is saved as NULL
+			require.Equal(t, sql.NullTime{}, args[3])
+			require.Equal(t, sql.NullInt64{}, args[4])
+		})
+	}
+}
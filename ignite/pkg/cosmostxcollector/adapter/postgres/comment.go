@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// errTxOptionsNotSupported is returned when a driver can't begin transactions with options.
+var errTxOptionsNotSupported = errors.New("driver doesn't support transaction options")
+
+// WithQueryComment configures an SQL comment to add at the beginning of each query.
+func WithQueryComment(comment string) Option {
+	return func(a *Adapter) {
+		a.queryComment = comment
+	}
+}
+
+// validateQueryComment checks that a query comment can be safely added to the queries.
+func validateQueryComment(comment string) error {
+	if strings.Contains(comment, "/*") || strings.Contains(comment, "*/") {
+		return fmt.Errorf("%w: query comment can't contain '/*' or '*/'", ErrInvalidOption)
+	}
+
+	return nil
+}
+
+// formatQueryComment returns the SQL block comment to add before the queries.
+func formatQueryComment(comment string) string {
+	return fmt.Sprintf("/* %s */ ", comment)
+}
+
+// commentConnector opens connections that add a comment to the queries.
+type commentConnector struct {
+	driver.Connector
+
+	prefix string
+}
+
+func (c commentConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return commentConn{conn, c.prefix}, nil
+}
+
+// commentConn adds a comment at the beginning of the queries run with a connection.
+// The optional connection interfaces are forwarded so the connection keeps working
+// like the connection of the driver.
+type commentConn struct {
+	driver.Conn
+
+	prefix string
+}
+
+func (c commentConn) Prepare(query string) (driver.Stmt, error) {
+	return c.Conn.Prepare(c.prefix + query)
+}
+
+func (c commentConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, c.prefix+query)
+	}
+
+	return c.Conn.Prepare(c.prefix + query)
+}
+
+func (c commentConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if e, ok := c.Conn.(driver.ExecerContext); ok {
+		return e.ExecContext(ctx, c.prefix+query, args)
+	}
+
+	return nil, driver.ErrSkip
+}
+
+func (c commentConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if q, ok := c.Conn.(driver.QueryerContext); ok {
+		return q.QueryContext(ctx, c.prefix+query, args)
+	}
+
+	return nil, driver.ErrSkip
+}
+
+func (c commentConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+
+	// Transactions can only be started with the default options
+	if opts.Isolation != driver.IsolationLevel(0) || opts.ReadOnly {
+		return nil, errTxOptionsNotSupported
+	}
+
+	return c.Conn.Begin() //nolint:staticcheck //ignore SA1019 only available way to begin a transaction
+}
+
+func (c commentConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c commentConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c commentConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+
+	return true
+}
+
+func (c commentConn) CheckNamedValue(v *driver.NamedValue) error {
+	if nc, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(v)
+	}
+
+	return driver.ErrSkip
+}
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryComment(t *testing.T) {
+	// Arrange
+	mockDB, mock, err := sqlmock.NewWithDSN(t.Name(), sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+
+	defer mockDB.Close()
+
+	c, err := openConnector("sqlmock", t.Name())
+	require.NoError(t, err)
+
+	db := sql.OpenDB(commentConnector{c, formatQueryComment("app:indexer")})
+	defer db.Close()
+
+	ctx := context.Background()
+
+	// Arrange: Database mock and expectations
+	mock.ExpectExec("/* app:indexer */ SELECT 1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectPrepare("/* app:indexer */ SELECT 2").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(2))
+	mock.ExpectCommit()
+
+	// Act
+	_, err = db.ExecContext(ctx, "SELECT 1")
+	require.NoError(t, err)
+
+	sqlTx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+
+	stmt, err := sqlTx.PrepareContext(ctx, "SELECT 2")
+	require.NoError(t, err)
+
+	var n int
+	require.NoError(t, stmt.QueryRowContext(ctx).Scan(&n))
+	require.NoError(t, stmt.Close())
+
+	err = sqlTx.Commit()
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, 2, n)
+}
+
+func TestUpdateSchemaErrorWithQueryComment(t *testing.T) {
+	// Arrange
+	mockDB, mock, err := sqlmock.NewWithDSN(t.Name(), sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+
+	defer mockDB.Close()
+
+	c, err := openConnector("sqlmock", t.Name())
+	require.NoError(t, err)
+
+	prefix := formatQueryComment("app:indexer:schema")
+	db := sql.OpenDB(commentConnector{c, prefix})
+	defer db.Close()
+
+	// Arrange: Schema file with an invalid statement
+	fs := fstest.MapFS{
+		"schemas/1.sql": &fstest.MapFile{Data: []byte("CREATE TABLE foo (id INT);\nCREATE TABLEE bar (id INT);\n")},
+	}
+	s := NewSchemas(fs, "")
+	adapter := Adapter{connOptions: connOptions{queryComment: "app:indexer:schema"}, schemaOptions: schemaOptions{schemas: s}, db: db}
+
+	var script string
+	err = s.WalkFrom(1, func(_ uint64, b []byte) error {
+		script = string(b)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Arrange: The error position is relative to the query with the comment
+	pos := strings.Index(prefix+script, "TABLEE") + 1
+	pgErr := &pgconn.PgError{Message: `syntax error at or near "TABLEE"`, Position: int32(pos)}
+
+	// Arrange: Database mock and expectations
+	mock.ExpectExec(prefix + sqlSchemaLock).WithArgs(s.tableName).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(prefix + s.GetTableDDL()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectQuery(prefix + s.GetSchemaVersionSQL()).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(uint64(0)))
+	mock.ExpectExec(prefix + script).WillReturnError(pgErr)
+	mock.ExpectExec(prefix + sqlSchemaUnlock).WithArgs(s.tableName).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err = adapter.UpdateSchema(context.Background(), s)
+
+	// Assert
+	var schemaErr SchemaError
+
+	require.ErrorAs(t, err, &schemaErr)
+	require.Equal(t, "CREATE TABLEE bar (id INT);", schemaErr.Statement)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestValidateQueryComment(t *testing.T) {
+	require.NoError(t, validateQueryComment("app:indexer"))
+	require.ErrorIs(t, validateQueryComment("foo */ DROP TABLE tx; /*"), ErrInvalidOption)
+	require.ErrorIs(t, validateQueryComment("/* nested"), ErrInvalidOption)
+}
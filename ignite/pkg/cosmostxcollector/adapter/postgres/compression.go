@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// rawCompressionMinSize defines the minimum size in bytes of the raw transactions that are compressed.
+// Smaller raw transactions are saved without compression because the savings are not worth it.
+const rawCompressionMinSize = 1024
+
+const sqlInsertRawTXCompressed = `
+	INSERT INTO raw_tx (hash, data_gz)
+	VALUES ($1, $2)
+`
+
+// gzipMagic contains the bytes that start the data compressed by gzip.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// WithRawCompression configures the adapter to gzip compress the raw transactions.
+func WithRawCompression(enabled bool) Option {
+	return func(a *Adapter) {
+		a.rawCompression = enabled
+	}
+}
+
+// compressRawTX compresses the data of a raw transaction when it is large enough.
+// It returns true when the data is compressed.
+func (a Adapter) compressRawTX(raw []byte) ([]byte, bool, error) {
+	if !a.rawCompression || len(raw) < rawCompressionMinSize {
+		return raw, false, nil
+	}
+
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(raw); err != nil {
+		return nil, false, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, false, err
+	}
+
+	return buf.Bytes(), true, nil
+}
+
+// decompressRawTX decompresses the data of a raw transaction when it is compressed.
+// Uncompressed data is JSON so it never starts with the gzip magic bytes.
+func decompressRawTX(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
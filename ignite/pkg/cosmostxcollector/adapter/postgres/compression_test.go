@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+func TestCompressRawTX(t *testing.T) {
+	// Arrange
+	adapter := mustWith(t, Adapter{}, WithRawCompression(true))
+	raw := bytes.Repeat([]byte(`{"key":"value"}`), 100)
+
+	// Act
+	data, compressed, err := adapter.compressRawTX(raw)
+	require.NoError(t, err)
+
+	got, err := decompressRawTX(data)
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, compressed)
+	require.Less(t, len(data), len(raw))
+	require.Equal(t, raw, got)
+}
+
+func TestCompressRawTXSmall(t *testing.T) {
+	// Arrange
+	adapter := mustWith(t, Adapter{}, WithRawCompression(true))
+	raw := []byte(`{"key":"value"}`)
+
+	// Act
+	data, compressed, err := adapter.compressRawTX(raw)
+
+	// Assert
+	require.NoError(t, err)
+	require.False(t, compressed)
+	require.Equal(t, raw, data)
+}
+
+func TestDecompressRawTXUncompressed(t *testing.T) {
+	// Arrange
+	raw := []byte(`{"key":"value"}`)
+
+	// Act
+	got, err := decompressRawTX(raw)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, raw, got)
+}
+
+func TestSaveOneWithRawCompression(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := mustWith(t, Adapter{db: db}, WithRawCompression(true))
+	tx := createLargeTestTX(t)
+	insertResult := sqlmock.NewResult(0, 1)
+
+	// Arrange: Database mock and expectations
+	mock.ExpectBegin()
+	mock.
+		ExpectExec(sqlInsertRawTXCompressed).
+		WithArgs(tx.Raw.Hash.String(), gzipMatcher{}).
+		WillReturnResult(insertResult)
+	mock.ExpectQuery(sqlInsertTX).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(sqlInsertEvent).WillReturnRows(sqlmock.NewRows([]string{"event_id"}).AddRow(1))
+	mock.ExpectExec(sqlInsertEventAttr).WillReturnResult(insertResult)
+	mock.ExpectCommit()
+
+	// Act
+	err := adapter.SaveOne(context.Background(), tx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTXAtHeightIndexCompressed(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+	tx := createLargeTestTX(t)
+
+	raw, err := json.Marshal(tx.Raw)
+	require.NoError(t, err)
+
+	data, compressed, err := mustWith(t, adapter, WithRawCompression(true)).compressRawTX(raw)
+	require.NoError(t, err)
+	require.True(t, compressed)
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(fmt.Sprintf(tplSelectTXsSQL, sqlTXAtHeightIndexClauses)).
+		WithArgs(int64(1), uint32(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"block_time", "data"}).AddRow(nil, data))
+
+	// Act
+	got, err := adapter.GetTXAtHeightIndex(context.Background(), 1, 0)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, tx.Raw.Hash, got.Raw.Hash)
+	require.Equal(t, tx.Raw.Tx, got.Raw.Tx)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func BenchmarkCompressRawTX(b *testing.B) {
+	adapter := mustWith(b, Adapter{}, WithRawCompression(true))
+	raw, err := json.Marshal(createLargeTestTX(b).Raw)
+	require.NoError(b, err)
+
+	var size int
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		data, _, err := adapter.compressRawTX(raw)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		size = len(data)
+	}
+
+	b.ReportMetric(float64(size)/float64(len(raw)), "ratio")
+}
+
+func BenchmarkDecompressRawTX(b *testing.B) {
+	adapter := mustWith(b, Adapter{}, WithRawCompression(true))
+	raw, err := json.Marshal(createLargeTestTX(b).Raw)
+	require.NoError(b, err)
+
+	data, _, err := adapter.compressRawTX(raw)
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := decompressRawTX(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// createLargeTestTX creates a transaction with a raw size that is large enough to be compressed.
+func createLargeTestTX(t testing.TB) cosmosclient.TX {
+	tx := createTestTX(t)
+	tx.Raw.Tx = bytes.Repeat([]byte("/cosmos.bank.v1beta1.MsgSend"), 100)
+
+	return tx
+}
+
+// gzipMatcher matches the SQL arguments that contain data compressed by gzip.
+type gzipMatcher struct{}
+
+func (gzipMatcher) Match(v driver.Value) bool {
+	data, ok := v.([]byte)
+	return ok && bytes.HasPrefix(data, gzipMagic)
+}
@@ -0,0 +1,67 @@
+package postgres
+
+// RedactedValue replaces the secret values of the adapter configuration.
+const RedactedValue = "REDACTED"
+
+// secretParams contains the names of the connection parameters with secret values.
+var secretParams = []string{"password", "sslpassword"}
+
+// ConfigSummary contains the database connection configuration of an adapter.
+type ConfigSummary struct {
+	// Host is the database host name or IP.
+	Host string
+
+	// Port is the database port.
+	Port uint
+
+	// Hosts contains the database hosts when more than one host is configured.
+	Hosts []HostPort
+
+	// User is the database user.
+	User string
+
+	// Password is RedactedValue when a password is configured, otherwise it is empty.
+	Password string
+
+	// Database is the name of the database.
+	Database string
+
+	// DriverName is the name of the SQL driver.
+	DriverName string
+
+	// Params contains the effective connection parameters, including the ones
+	// configured with specific options. The secret values are redacted.
+	Params map[string]string
+}
+
+// Config returns a summary of the database connection configuration.
+// It allows checking how the adapter is configured, for example when debugging
+// connection issues. Passwords are redacted so the summary can be safely logged.
+func (a Adapter) Config() ConfigSummary {
+	c := ConfigSummary{
+		Host:       a.host,
+		Port:       a.port,
+		Hosts:      append([]HostPort(nil), a.hosts...),
+		User:       a.user,
+		Database:   a.database,
+		DriverName: a.driverName,
+		Params:     map[string]string{},
+	}
+
+	if a.password != "" {
+		c.Password = RedactedValue
+	}
+
+	params := connParams(a)
+	for k := range params {
+		c.Params[k] = params.Get(k)
+	}
+
+	for _, k := range secretParams {
+		if _, ok := c.Params[k]; ok {
+			c.Params[k] = RedactedValue
+		}
+	}
+
+	return c
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	// Arrange
+	base := Adapter{
+		connOptions: connOptions{
+			host:       DefaultHost,
+			port:       DefaultPort,
+			database:   "cosmos",
+			driverName: DefaultDriverName,
+		},
+	}
+	adapter := mustWith(t, base,
+		WithUser("foo"),
+		WithPassword("secret"),
+		WithParam(paramSSLMode, "disable"),
+		WithParam("sslpassword", "secret"),
+		WithSearchPath("indexer"),
+	)
+	want := ConfigSummary{
+		Host:       DefaultHost,
+		Port:       DefaultPort,
+		User:       "foo",
+		Password:   RedactedValue,
+		Database:   "cosmos",
+		DriverName: DefaultDriverName,
+		Params: map[string]string{
+			paramSSLMode:  "disable",
+			"sslpassword": RedactedValue,
+			paramOptions:  `-c search_path="indexer"`,
+		},
+	}
+
+	// Act
+	c := adapter.Config()
+
+	// Assert
+	require.Equal(t, want, c)
+}
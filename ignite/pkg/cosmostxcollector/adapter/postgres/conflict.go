@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const (
+	// ConflictError returns an error when a saved row already exists.
+	ConflictError ConflictPolicy = ""
+
+	// ConflictIgnore keeps the existing row when a saved row already exists.
+	ConflictIgnore ConflictPolicy = "ignore"
+
+	// ConflictUpdate replaces the values of the existing row when a saved row already exists.
+	ConflictUpdate ConflictPolicy = "update"
+)
+
+const (
+	sqlAttrConflictIgnore = `
+		ON CONFLICT (event_id, name) DO NOTHING
+	`
+	sqlAttrConflictUpdate = `
+		ON CONFLICT (event_id, name) DO UPDATE
+		SET value = EXCLUDED.value, value_type = EXCLUDED.value_type, index = EXCLUDED.index
+	`
+	sqlUpsertTX = `
+		INSERT INTO tx (hash, index, height, block_time, block_time_ms, code, codespace, chain_id, created_at, raw_log)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (hash) DO UPDATE
+		SET index = EXCLUDED.index, height = EXCLUDED.height, block_time = EXCLUDED.block_time,
+			block_time_ms = EXCLUDED.block_time_ms, code = EXCLUDED.code, codespace = EXCLUDED.codespace,
+			chain_id = EXCLUDED.chain_id, raw_log = EXCLUDED.raw_log
+		RETURNING id
+	`
+	sqlRawTXConflictUpdate = `
+		ON CONFLICT (hash) DO UPDATE SET data = EXCLUDED.data, data_gz = EXCLUDED.data_gz
+	`
+	sqlDeleteTXEvents = `
+		DELETE FROM event WHERE tx_hash = $1
+	`
+)
+
+// ConflictPolicy defines what to do when a saved row already exists in the database.
+type ConflictPolicy string
+
+// WithAttributeConflictPolicy configures what to do when a saved event attribute already exists.
+func WithAttributeConflictPolicy(p ConflictPolicy) Option {
+	return func(a *Adapter) {
+		a.attrConflictPolicy = p
+	}
+}
+
+// WithTXConflictPolicy configures what to do when a saved transaction already exists.
+func WithTXConflictPolicy(p ConflictPolicy) Option {
+	return func(a *Adapter) {
+		a.txConflictPolicy = p
+	}
+}
+
+// validateTXConflictPolicy checks that a conflict policy is supported for the transactions.
+func validateTXConflictPolicy(p ConflictPolicy) error {
+	if p == ConflictIgnore {
+		return fmt.Errorf("%w: transaction conflict policy '%s' is not supported", ErrInvalidOption, p)
+	}
+
+	return validateConflictPolicy(p)
+}
+
+// validateConflictPolicy checks that a conflict policy is supported.
+func validateConflictPolicy(p ConflictPolicy) error {
+	switch p {
+	case ConflictError, ConflictIgnore, ConflictUpdate:
+		return nil
+	}
+
+	return fmt.Errorf("%w: conflict policy '%s' is not supported", ErrInvalidOption, p)
+}
+
+// getAttrInsertSQL returns the SQL to insert an event attribute using the attribute conflict policy.
+func (a Adapter) getAttrInsertSQL() string {
+	switch a.attrConflictPolicy {
+	case ConflictIgnore:
+		return sqlInsertEventAttr + sqlAttrConflictIgnore
+	case ConflictUpdate:
+		return sqlInsertEventAttr + sqlAttrConflictUpdate
+	case ConflictError:
+	}
+
+	return sqlInsertEventAttr
+}
+
+// getTXInsertSQL returns the SQL to insert a transaction using the transaction conflict policy.
+// The SQL merges the transaction when MERGE is used to update the existing transactions.
+func (a Adapter) getTXInsertSQL(merge bool) string {
+	if a.txConflictPolicy != ConflictUpdate {
+		return sqlInsertTX
+	}
+
+	if merge {
+		return sqlMergeTX
+	}
+
+	return sqlUpsertTX
+}
+
+// getRawTXInsertSQL returns the SQL to insert a raw transaction using the transaction conflict policy.
+func (a Adapter) getRawTXInsertSQL(compressed bool) string {
+	query := sqlInsertRawTX
+	if compressed {
+		query = sqlInsertRawTXCompressed
+	}
+
+	if a.txConflictPolicy == ConflictUpdate {
+		return query + sqlRawTXConflictUpdate
+	}
+
+	return query
+}
+
+// deleteTXEvents deletes the events of a transaction when existing transactions are updated.
+func (a Adapter) deleteTXEvents(ctx context.Context, sqlTx *sql.Tx, hash string) error {
+	if a.txConflictPolicy != ConflictUpdate {
+		return nil
+	}
+
+	if _, err := sqlTx.ExecContext(ctx, sqlDeleteTXEvents, hash); err != nil {
+		return fmt.Errorf("error deleting events of TX %s: %w", hash, err)
+	}
+
+	return nil
+}
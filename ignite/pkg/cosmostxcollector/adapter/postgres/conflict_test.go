@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAttrInsertSQL(t *testing.T) {
+	cases := []struct {
+		policy ConflictPolicy
+		want   string
+	}{
+		{ConflictError, sqlInsertEventAttr},
+		{ConflictIgnore, sqlInsertEventAttr + sqlAttrConflictIgnore},
+		{ConflictUpdate, sqlInsertEventAttr + sqlAttrConflictUpdate},
+	}
+
+	for _, tt := range cases {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			// Arrange
+			a := mustWith(t, Adapter{}, WithAttributeConflictPolicy(tt.policy))
+
+			// Act
+			got := a.getAttrInsertSQL()
+
+			// Assert
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSaveOneWithAttributeConflictPolicy(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := mustWith(t, Adapter{db: db}, WithAttributeConflictPolicy(ConflictIgnore))
+	insertResult := sqlmock.NewResult(0, 1)
+
+	// Arrange: Database mock and expectations
+	mock.ExpectBegin()
+	mock.ExpectExec(sqlInsertRawTX).WillReturnResult(insertResult)
+	mock.ExpectQuery(sqlInsertTX).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(sqlInsertEvent).WillReturnRows(sqlmock.NewRows([]string{"event_id"}).AddRow(1))
+	mock.ExpectExec(sqlInsertEventAttr + sqlAttrConflictIgnore).WillReturnResult(insertResult)
+	mock.ExpectCommit()
+
+	// Act
+	err := adapter.SaveOne(context.Background(), createTestTX(t))
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTXInsertSQL(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy ConflictPolicy
+		merge  bool
+		want   string
+	}{
+		{"error", ConflictError, false, sqlInsertTX},
+		{"error with merge", ConflictError, true, sqlInsertTX},
+		{"update", ConflictUpdate, false, sqlUpsertTX},
+		{"update with merge", ConflictUpdate, true, sqlMergeTX},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			a := mustWith(t, Adapter{}, WithTXConflictPolicy(tt.policy))
+
+			// Act
+			got := a.getTXInsertSQL(tt.merge)
+
+			// Assert
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateTXConflictPolicy(t *testing.T) {
+	require.NoError(t, validateTXConflictPolicy(ConflictError))
+	require.NoError(t, validateTXConflictPolicy(ConflictUpdate))
+	require.ErrorIs(t, validateTXConflictPolicy(ConflictIgnore), ErrInvalidOption)
+	require.ErrorIs(t, validateTXConflictPolicy("replace"), ErrInvalidOption)
+}
+
+func TestSaveOneWithTXConflictPolicy(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := mustWith(t, Adapter{db: db}, WithTXConflictPolicy(ConflictUpdate))
+	tx := createTestTX(t)
+	hash := tx.Raw.Hash.String()
+	insertResult := sqlmock.NewResult(0, 1)
+
+	// Arrange: Database mock and expectations
+	mock.ExpectBegin()
+	mock.ExpectExec(sqlInsertRawTX + sqlRawTXConflictUpdate).WillReturnResult(insertResult)
+	mock.ExpectExec(sqlDeleteTXEvents).WithArgs(hash).WillReturnResult(insertResult)
+	mock.ExpectQuery(sqlUpsertTX).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(sqlInsertEvent).WillReturnRows(sqlmock.NewRows([]string{"event_id"}).AddRow(1))
+	mock.ExpectExec(sqlInsertEventAttr).WillReturnResult(insertResult)
+	mock.ExpectCommit()
+
+	// Act
+	err := adapter.SaveOne(context.Background(), tx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// ConnectionHook defines a function that is called for each new database connection.
+type ConnectionHook func(ctx context.Context, conn *sql.Conn) error
+
+// WithConnectionHook configures a function to call each time a new database connection is opened.
+func WithConnectionHook(fn ConnectionHook) Option {
+	return func(a *Adapter) {
+		// Copy the hooks to avoid changing a slice that is shared
+		hooks := make([]ConnectionHook, len(a.connHooks), len(a.connHooks)+1)
+		copy(hooks, a.connHooks)
+		a.connHooks = append(hooks, fn)
+	}
+}
+
+// openConnector returns a connector for a driver.
+func openConnector(driverName, dsn string) (driver.Connector, error) {
+	// The driver is only available through a database handle
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	drv := db.Driver()
+	db.Close()
+
+	if dc, ok := drv.(driver.DriverContext); ok {
+		return dc.OpenConnector(dsn)
+	}
+
+	return dsnConnector{dsn, drv}, nil
+}
+
+// dsnConnector opens connections using a driver that doesn't provide its own connector.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// hookConnector calls the connection hooks each time a new connection is opened.
+type hookConnector struct {
+	driver.Connector
+
+	hooks []ConnectionHook
+}
+
+func (c hookConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.runHooks(ctx, conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("connection hook failed: %w", err)
+	}
+
+	return conn, nil
+}
+
+// runHooks calls the hooks using a database handle that only has the new connection.
+func (c hookConnector) runHooks(ctx context.Context, conn driver.Conn) error {
+	db := sql.OpenDB(singleConnector{hookConn{conn}, c.Driver()})
+	defer db.Close()
+
+	// Only one connection must be open, which is the one being configured
+	db.SetMaxOpenConns(1)
+
+	sqlConn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer sqlConn.Close()
+
+	for _, fn := range c.hooks {
+		if err := fn(ctx, sqlConn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// singleConnector always returns the same connection.
+type singleConnector struct {
+	conn   driver.Conn
+	driver driver.Driver
+}
+
+func (c singleConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.conn, nil
+}
+
+func (c singleConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// hookConn wraps a connection while the hooks are called.
+// Closing the connection has no effect so it can be used after the hooks.
+type hookConn struct {
+	driver.Conn
+}
+
+func (c hookConn) Close() error {
+	return nil
+}
+
+func (c hookConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if e, ok := c.Conn.(driver.ExecerContext); ok {
+		return e.ExecContext(ctx, query, args)
+	}
+
+	return nil, driver.ErrSkip
+}
+
+func (c hookConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if q, ok := c.Conn.(driver.QueryerContext); ok {
+		return q.QueryContext(ctx, query, args)
+	}
+
+	return nil, driver.ErrSkip
+}
+
+func (c hookConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+
+	return c.Conn.Prepare(query)
+}
+
+func (c hookConn) CheckNamedValue(v *driver.NamedValue) error {
+	if nc, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(v)
+	}
+
+	return driver.ErrSkip
+}
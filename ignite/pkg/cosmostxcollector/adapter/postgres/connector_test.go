@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionHook(t *testing.T) {
+	// Arrange
+	mockDB, mock, err := sqlmock.NewWithDSN(t.Name(), sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+
+	defer mockDB.Close()
+
+	var calls []string
+
+	a := mustWith(t, Adapter{},
+		WithConnectionHook(func(ctx context.Context, conn *sql.Conn) error {
+			calls = append(calls, "timezone")
+			_, err := conn.ExecContext(ctx, "SET TIME ZONE 'UTC'")
+			return err
+		}),
+		WithConnectionHook(func(context.Context, *sql.Conn) error {
+			calls = append(calls, "second")
+			return nil
+		}),
+	)
+
+	c, err := openConnector("sqlmock", t.Name())
+	require.NoError(t, err)
+
+	db := sql.OpenDB(hookConnector{c, a.connHooks})
+	defer db.Close()
+
+	// Arrange: Database mock and expectations
+	mock.ExpectExec("SET TIME ZONE 'UTC'").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	_, err = db.ExecContext(context.Background(), "SELECT 1")
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, []string{"timezone", "second"}, calls)
+}
+
+func TestConnectionHookError(t *testing.T) {
+	// Arrange
+	mockDB, mock, err := sqlmock.NewWithDSN(t.Name())
+	require.NoError(t, err)
+
+	defer mockDB.Close()
+
+	wantErr := errors.New("hook failed")
+	hooks := []ConnectionHook{
+		func(context.Context, *sql.Conn) error {
+			return wantErr
+		},
+	}
+
+	c, err := openConnector("sqlmock", t.Name())
+	require.NoError(t, err)
+
+	db := sql.OpenDB(hookConnector{c, hooks})
+	defer db.Close()
+
+	// Act
+	err = db.PingContext(context.Background())
+
+	// Assert
+	require.ErrorIs(t, err, wantErr)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
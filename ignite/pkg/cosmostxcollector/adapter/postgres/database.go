@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// CreateDatabaseEnv is the environment variable that must be set to "true"
+	// to allow creating the database with WithCreateDatabase.
+	CreateDatabaseEnv = "COSMOSTXCOLLECTOR_CREATE_DATABASE"
+
+	// maintenanceDatabase is the name of the database used to create the adapter database.
+	maintenanceDatabase = "postgres"
+
+	// errCodeInsufficientPrivilege is the SQLSTATE code returned when the role lacks a privilege.
+	errCodeInsufficientPrivilege = "42501"
+
+	sqlSelectDatabaseExists = `
+		SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)
+	`
+	tplCreateDatabaseSQL = `
+		CREATE DATABASE %s
+	`
+)
+
+// WithCreateDatabase creates the database when it doesn't exist, only allowed when CreateDatabaseEnv is "true".
+func WithCreateDatabase() Option {
+	return func(a *Adapter) {
+		a.createDatabase = true
+	}
+}
+
+// validateCreateDatabase checks that creating the database is allowed by the environment.
+func validateCreateDatabase(createDatabase bool) error {
+	if createDatabase && os.Getenv(CreateDatabaseEnv) != "true" {
+		return fmt.Errorf("%w: creating the database requires %s to be set to \"true\"", ErrInvalidOption, CreateDatabaseEnv)
+	}
+
+	return nil
+}
+
+// createDatabase creates the adapter database when it doesn't exist.
+func createDatabase(ctx context.Context, a Adapter) error {
+	// The connection hooks are not used because they are meant for the adapter database
+	m := a
+	m.database = maintenanceDatabase
+	m.connHooks = nil
+
+	db, err := openPool(m)
+	if err != nil {
+		return fmt.Errorf("failed to create database '%s': %w", a.database, err)
+	}
+
+	defer db.Close()
+
+	return ensureDatabase(ctx, db, a.database)
+}
+
+// ensureDatabase creates a database when it doesn't exist.
+func ensureDatabase(ctx context.Context, db *sql.DB, name string) error {
+	var exists bool
+	if err := db.QueryRowContext(ctx, sqlSelectDatabaseExists, name).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to create database '%s': %w", name, err)
+	}
+
+	if exists {
+		return nil
+	}
+
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(tplCreateDatabaseSQL, pq.QuoteIdentifier(name))); err != nil {
+		if ErrorCode(err) == errCodeInsufficientPrivilege {
+			return fmt.Errorf("failed to create database '%s', the role doesn't have the CREATEDB privilege: %w", name, err)
+		}
+
+		return fmt.Errorf("failed to create database '%s': %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureDatabase(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectDatabaseExists).
+		WithArgs("cosmos").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.
+		ExpectExec(fmt.Sprintf(tplCreateDatabaseSQL, `"cosmos"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := ensureDatabase(context.Background(), db, "cosmos")
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnsureDatabaseExists(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectDatabaseExists).
+		WithArgs("cosmos").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	// Act
+	err := ensureDatabase(context.Background(), db, "cosmos")
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnsureDatabaseWithoutPrivilege(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectDatabaseExists).
+		WithArgs("cosmos").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.
+		ExpectExec(fmt.Sprintf(tplCreateDatabaseSQL, `"cosmos"`)).
+		WillReturnError(&pq.Error{Code: errCodeInsufficientPrivilege})
+
+	// Act
+	err := ensureDatabase(context.Background(), db, "cosmos")
+
+	// Assert
+	require.ErrorContains(t, err, "CREATEDB privilege")
+	require.Equal(t, errCodeInsufficientPrivilege, ErrorCode(err))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestValidateCreateDatabase(t *testing.T) {
+	cases := []struct {
+		name    string
+		env     string
+		create  bool
+		wantErr error
+	}{
+		{
+			name:   "disabled",
+			create: false,
+		},
+		{
+			name:   "allowed",
+			env:    "true",
+			create: true,
+		},
+		{
+			name:    "not allowed",
+			create:  true,
+			wantErr: ErrInvalidOption,
+		},
+		{
+			name:    "invalid value",
+			env:     "1",
+			create:  true,
+			wantErr: ErrInvalidOption,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv(CreateDatabaseEnv, tt.env)
+
+			// Act
+			err := validateCreateDatabase(tt.create)
+
+			// Assert
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewAdapterWithCreateDatabaseNotAllowed(t *testing.T) {
+	t.Setenv(CreateDatabaseEnv, "")
+
+	_, err := NewAdapter("cosmos", WithCreateDatabase())
+
+	require.ErrorIs(t, err, ErrInvalidOption)
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	sqlSelectTXStats = `
+		SELECT COALESCE(MAX(height), 0), COUNT(*)
+		FROM tx
+	`
+	sqlShowServerVersion = `
+		SHOW server_version_num
+	`
+)
+
+// Diag contains diagnostic information about the database.
+type Diag struct {
+	// SchemaVersion is the current version of the database schema.
+	// It is zero when no schema has been applied to the database.
+	SchemaVersion uint
+
+	// LatestHeight is the block height of the latest saved transaction.
+	LatestHeight int64
+
+	// TXCount is the number of saved transactions.
+	TXCount int64
+
+	// PingLatency is the time it took to check the database connection.
+	PingLatency time.Duration
+}
+
+// Diagnostics returns diagnostic information about the database.
+// It checks that the database connection is alive and measures the time it takes,
+// and then reads the schema version and the transaction stats.
+func (a Adapter) Diagnostics(ctx context.Context) (d Diag, err error) {
+	defer func() { a.recordStatus(err) }()
+
+	db, err := a.getDB()
+	if err != nil {
+		return Diag{}, err
+	}
+
+	start := time.Now()
+	if err := db.PingContext(ctx); err != nil {
+		return Diag{}, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	d.PingLatency = time.Since(start)
+
+	if d.SchemaVersion, err = getSchemaVersion(ctx, db, a.schemas); err != nil {
+		return Diag{}, err
+	}
+
+	// The transactions table doesn't exist until the first schema is applied
+	if d.SchemaVersion == 0 {
+		return d, nil
+	}
+
+	row := db.QueryRowContext(ctx, sqlSelectTXStats)
+	if err := row.Scan(&d.LatestHeight, &d.TXCount); err != nil {
+		return Diag{}, fmt.Errorf("failed to read transaction stats: %w", err)
+	}
+
+	return d, nil
+}
+
+// ServerVersion returns the version number of the PostgreSQL server.
+// The version number is an integer that can be compared with other versions,
+// for example version 15.2 is returned as 150002.
+func (a Adapter) ServerVersion(ctx context.Context) (version int, err error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return 0, err
+	}
+
+	err = a.withRetry(ctx, func() error {
+		return db.QueryRowContext(ctx, sqlShowServerVersion).Scan(&version)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read server version: %w", err)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	schemas := NewSchemas(fsSchemas, "")
+	adapter := Adapter{schemaOptions: schemaOptions{schemas: schemas}, db: db}
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectTableExists).
+		WithArgs(schemas.tableName).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.
+		ExpectQuery(schemas.GetSchemaVersionSQL()).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+	mock.
+		ExpectQuery(sqlSelectTXStats).
+		WillReturnRows(sqlmock.NewRows([]string{"height", "count"}).AddRow(42, 7))
+
+	// Act
+	d, err := adapter.Diagnostics(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, uint(5), d.SchemaVersion)
+	require.EqualValues(t, 42, d.LatestHeight)
+	require.EqualValues(t, 7, d.TXCount)
+}
+
+func TestDiagnosticsWithoutSchema(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	schemas := NewSchemas(fsSchemas, "")
+	adapter := Adapter{schemaOptions: schemaOptions{schemas: schemas}, db: db}
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectTableExists).
+		WithArgs(schemas.tableName).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	// Act
+	d, err := adapter.Diagnostics(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, Diag{PingLatency: d.PingLatency}, d)
+}
+
+func TestDiagnosticsClosed(t *testing.T) {
+	_, err := Adapter{}.Diagnostics(context.Background())
+
+	require.ErrorIs(t, err, ErrClosed)
+}
+
+func TestServerVersion(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlShowServerVersion).
+		WillReturnRows(sqlmock.NewRows([]string{"server_version_num"}).AddRow("150002"))
+
+	// Act
+	version, err := adapter.ServerVersion(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, 150002, version)
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/lib/pq"
+)
+
+// defaultKeepAlive is the period between keepalive probes when keepalives are enabled.
+const defaultKeepAlive = 15 * time.Second
+
+// defaultDialer is the dialer used when the adapter doesn't have one.
+var defaultDialer net.Dialer
+
+// DialFunc defines a function to open the network connections to the database.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// WithDialer configures the function used to open the network connections to the database.
+func WithDialer(fn DialFunc) Option {
+	return func(a *Adapter) {
+		a.dialer = fn
+	}
+}
+
+// WithKeepalives configures the use of TCP keepalives for the database connections.
+func WithKeepalives(enabled bool) Option {
+	return func(a *Adapter) {
+		if enabled {
+			a.keepAlive = defaultKeepAlive
+		} else {
+			a.keepAlive = -1
+		}
+	}
+}
+
+// dialFunc returns the function used to open the network connections to the database.
+// Nil is returned when the connections are opened by the driver.
+func (a Adapter) dialFunc() DialFunc {
+	if a.keepAlive == 0 {
+		return a.dialer
+	}
+
+	if a.dialer == nil {
+		d := net.Dialer{KeepAlive: a.keepAlive}
+		return d.DialContext
+	}
+
+	// The keepalives are configured for the TCP connections opened by the dialer
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := a.dialer(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		if tc, ok := conn.(*net.TCPConn); ok {
+			if err := setKeepAlive(tc, a.keepAlive); err != nil {
+				conn.Close()
+				return nil, err
+			}
+		}
+
+		return conn, nil
+	}
+}
+
+// setKeepAlive enables the keepalives of a TCP connection using a period, or disables them when the period is negative.
+func setKeepAlive(conn *net.TCPConn, period time.Duration) error {
+	if period < 0 {
+		return conn.SetKeepAlive(false)
+	}
+
+	if err := conn.SetKeepAlive(true); err != nil {
+		return err
+	}
+
+	return conn.SetKeepAlivePeriod(period)
+}
+
+// openDialConnector returns a connector that opens the connections using a dialer.
+func openDialConnector(driverName, dsn string, dial DialFunc) (driver.Connector, error) {
+	switch driverName {
+	case DefaultDriverName:
+		c, err := pq.NewConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Dialer(pqDialer(dial))
+
+		return c, nil
+	case PgxDriverName:
+		cfg, err := parsePgxConfig(dsn, dial)
+		if err != nil {
+			return nil, err
+		}
+
+		return stdlib.GetConnector(*cfg), nil
+	}
+
+	return nil, fmt.Errorf("%w: dialer", ErrUnsupportedOption)
+}
+
+// parsePgxConfig parses the pgx connection configuration and configures it to use a dialer when there is one.
+func parsePgxConfig(dsn string, dial DialFunc) (*pgx.ConnConfig, error) {
+	cfg, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if dial != nil {
+		// The host names are resolved by the dialer because the
+		// hosts could only be reachable through it
+		cfg.DialFunc = pgconn.DialFunc(dial)
+		cfg.LookupFunc = func(_ context.Context, host string) ([]string, error) {
+			return []string{host}, nil
+		}
+	}
+
+	return cfg, nil
+}
+
+// pqDialer adapts a dial function to the dialer interfaces of the default driver.
+type pqDialer DialFunc
+
+func (d pqDialer) Dial(network, address string) (net.Conn, error) {
+	return d(context.Background(), network, address)
+}
+
+func (d pqDialer) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return d(ctx, network, address)
+}
+
+func (d pqDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return d(ctx, network, address)
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDialer(t *testing.T) {
+	cases := []struct {
+		name    string
+		options []Option
+	}{
+		{
+			name: "default driver",
+		},
+		{
+			name:    "pgx driver",
+			options: []Option{WithPgxDriver()},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var addrs []string
+
+			wantErr := errors.New("dial failed")
+			dial := func(_ context.Context, _, addr string) (net.Conn, error) {
+				addrs = append(addrs, addr)
+				return nil, wantErr
+			}
+
+			options := append(tt.options, WithHost("db"), WithPort(5433), WithDialer(dial))
+			a, err := NewAdapter("test", options...)
+			require.NoError(t, err)
+
+			defer a.db.Close()
+
+			// Act
+			err = a.db.PingContext(context.Background())
+
+			// Assert
+			require.ErrorIs(t, err, wantErr)
+			require.Contains(t, addrs, "db:5433")
+		})
+	}
+}
+
+func TestWithDialerUnsupportedDriver(t *testing.T) {
+	dial := func(context.Context, string, string) (net.Conn, error) {
+		return nil, nil
+	}
+
+	_, err := NewAdapter("test", WithDriverName("custom"), WithDialer(dial))
+
+	require.ErrorIs(t, err, ErrUnsupportedOption)
+}
+
+func TestWithKeepalivesUnsupportedDriver(t *testing.T) {
+	_, err := NewAdapter("test", WithDriverName("custom"), WithKeepalives(true))
+
+	require.ErrorIs(t, err, ErrUnsupportedOption)
+}
+
+func TestDialFunc(t *testing.T) {
+	// Arrange
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer l.Close()
+
+	var dialed bool
+
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = true
+		return defaultDialer.DialContext(ctx, network, addr)
+	}
+
+	cases := []struct {
+		name    string
+		adapter Adapter
+		dialer  bool
+		nilFunc bool
+	}{
+		{
+			name:    "default",
+			nilFunc: true,
+		},
+		{
+			name:    "keepalives",
+			adapter: Adapter{connOptions: connOptions{keepAlive: defaultKeepAlive}},
+		},
+		{
+			name:    "keepalives disabled with dialer",
+			adapter: Adapter{connOptions: connOptions{keepAlive: -1, dialer: dial}},
+			dialer:  true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			dialed = false
+
+			// Act
+			fn := tt.adapter.dialFunc()
+
+			// Assert
+			if tt.nilFunc {
+				require.Nil(t, fn)
+				return
+			}
+
+			conn, err := fn(context.Background(), "tcp", l.Addr().String())
+			require.NoError(t, err)
+			require.NoError(t, conn.Close())
+			require.Equal(t, tt.dialer, dialed)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables used by PostgreSQL tools to configure the database connection.
+const (
+	EnvHost     = "PGHOST"
+	EnvPort     = "PGPORT"
+	EnvUser     = "PGUSER"
+	EnvPassword = "PGPASSWORD"
+	EnvDatabase = "PGDATABASE"
+	EnvSSLMode  = "PGSSLMODE"
+)
+
+// NewAdapterFromEnv creates a new PostgreSQL adapter configured with the standard
+// PostgreSQL environment variables: PGHOST, PGPORT, PGUSER, PGPASSWORD, PGDATABASE
+// and PGSSLMODE. The default values are used for the variables that are not defined,
+// and like with other PostgreSQL tools the user name is used as database name when
+// PGDATABASE is not defined. Options are applied after the environment variables,
+// so they can be used to override them.
+func NewAdapterFromEnv(options ...Option) (Adapter, error) {
+	var envOptions []Option
+
+	if v := os.Getenv(EnvHost); v != "" {
+		envOptions = append(envOptions, WithHost(v))
+	}
+
+	if v := os.Getenv(EnvPort); v != "" {
+		port, err := strconv.ParseUint(v, 10, 16)
+		if err != nil {
+			return Adapter{}, fmt.Errorf("%w: %s '%s' is not a valid port", ErrInvalidOption, EnvPort, v)
+		}
+
+		envOptions = append(envOptions, WithPort(uint(port)))
+	}
+
+	user := os.Getenv(EnvUser)
+	if user != "" {
+		envOptions = append(envOptions, WithUser(user))
+	}
+
+	if v := os.Getenv(EnvPassword); v != "" {
+		envOptions = append(envOptions, WithPassword(v))
+	}
+
+	if v := os.Getenv(EnvSSLMode); v != "" {
+		envOptions = append(envOptions, WithParam(paramSSLMode, v))
+	}
+
+	database := os.Getenv(EnvDatabase)
+	if database == "" {
+		database = user
+	}
+
+	return NewAdapter(database, append(envOptions, options...)...)
+}
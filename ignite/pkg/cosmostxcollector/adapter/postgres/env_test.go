@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAdapterFromEnv(t *testing.T) {
+	// Arrange
+	t.Setenv(EnvHost, "db.example.com")
+	t.Setenv(EnvPort, "6432")
+	t.Setenv(EnvUser, "cosmos")
+	t.Setenv(EnvPassword, "secret")
+	t.Setenv(EnvDatabase, "")
+	t.Setenv(EnvSSLMode, "require")
+
+	// Act
+	adapter, err := NewAdapterFromEnv(WithPort(7432))
+
+	// Assert
+	require.NoError(t, err)
+
+	defer adapter.Close()
+
+	require.Equal(t, "db.example.com", adapter.host)
+	require.Equal(t, uint(7432), adapter.port)
+	require.Equal(t, "cosmos", adapter.user)
+	require.Equal(t, "secret", adapter.password)
+	require.Equal(t, "cosmos", adapter.database)
+	require.Equal(t, map[string]string{paramSSLMode: "require"}, adapter.params)
+}
+
+func TestNewAdapterFromEnvInvalidPort(t *testing.T) {
+	// Arrange
+	t.Setenv(EnvPort, "foo")
+
+	// Act
+	_, err := NewAdapterFromEnv()
+
+	// Assert
+	require.ErrorIs(t, err, ErrInvalidOption)
+}
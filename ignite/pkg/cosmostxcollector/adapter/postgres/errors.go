@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// ErrorCode returns the PostgreSQL SQLSTATE code of an error.
+// The error is unwrapped until a PostgreSQL error is found, either from the default
+// driver or from pgx. An empty string is returned when the error is not a PostgreSQL error.
+func ErrorCode(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+
+	return ""
+}
+
+// OptionErrors is returned when one or more adapter options are not valid.
+// It contains one error for each invalid option.
+type OptionErrors []error
+
+func (e OptionErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return fmt.Sprintf("invalid adapter options: %s", strings.Join(msgs, "; "))
+}
+
+// Is checks if any of the option errors matches the target error.
+func (e OptionErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// add adds an error when it is not nil.
+func (e *OptionErrors) add(err error) {
+	if err != nil {
+		*e = append(*e, err)
+	}
+}
+
+// SaveError is returned when a transaction can't be saved.
+type SaveError struct {
+	// Index is the position of the transaction in the list of saved transactions.
+	Index int
+
+	// Hash is the hash of the transaction.
+	Hash string
+
+	// Err is the reason why the transaction can't be saved.
+	Err error
+}
+
+func (e SaveError) Error() string {
+	return fmt.Sprintf("error saving TX #%d '%s': %v", e.Index, e.Hash, e.Err)
+}
+
+func (e SaveError) Unwrap() error {
+	return e.Err
+}
+
+// SchemaError is returned when a schema script can't be applied.
+type SchemaError struct {
+	// Version is the version of the schema.
+	Version uint
+
+	// Statement is the SQL statement of the script that failed.
+	// It is empty when the failed statement is not known.
+	Statement string
+
+	// Err is the reason why the schema script can't be applied.
+	Err error
+}
+
+func (e SchemaError) Error() string {
+	if e.Statement == "" {
+		return fmt.Sprintf("error applying schema version %d: %v", e.Version, e.Err)
+	}
+
+	return fmt.Sprintf("error applying schema version %d: %v: %s", e.Version, e.Err, e.Statement)
+}
+
+func (e SchemaError) Unwrap() error {
+	return e.Err
+}
+
+// newSchemaError creates a new schema error for a schema script.
+// The failed statement is extracted from the script using the error
+// position when the error is a PostgreSQL error. The position is relative
+// to the query sent to the server, so the query comment is skipped.
+func (a Adapter) newSchemaError(version uint, script []byte, err error) SchemaError {
+	var offset int
+	if a.queryComment != "" {
+		offset = utf8.RuneCountInString(formatQueryComment(a.queryComment))
+	}
+
+	return SchemaError{
+		Version:   version,
+		Statement: extractErrorStatement(script, offset, err),
+		Err:       err,
+	}
+}
+
+// extractErrorStatement returns the statement of a script where an error happened.
+// The offset is the number of characters sent to the server before the script.
+func extractErrorStatement(script []byte, offset int, err error) string {
+	// The error position is the index of a character starting from one
+	pos := errorPosition(err) - offset
+	chars := bytes.Runes(script)
+	if pos < 1 || pos > len(chars) {
+		return ""
+	}
+
+	s := []byte(string(chars[:pos-1]))
+	start := bytes.LastIndexByte(s, ';') + 1
+	end := len(script)
+	if i := bytes.IndexByte(script[len(s):], ';'); i >= 0 {
+		end = len(s) + i + 1
+	}
+
+	return string(bytes.TrimSpace(script[start:end]))
+}
+
+// errorPosition returns the position of the query character where a PostgreSQL error happened.
+// Zero is returned when the error is not a PostgreSQL error or when the position is not known.
+func errorPosition(err error) int {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		pos, _ := strconv.Atoi(pqErr.Position)
+		return pos
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return int(pgErr.Position)
+	}
+
+	return 0
+}
@@ -0,0 +1,66 @@
+package postgres_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ignite/cli/ignite/pkg/cosmostxcollector/adapter/postgres"
+)
+
+func TestErrorCode(t *testing.T) {
+	uniqueViolation := &pq.Error{Code: "23505"}
+
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "postgres error",
+			err:  uniqueViolation,
+			want: "23505",
+		},
+		{
+			name: "wrapped postgres error",
+			err:  fmt.Errorf("error saving TX: %w", uniqueViolation),
+			want: "23505",
+		},
+		{
+			name: "pgx error",
+			err:  fmt.Errorf("error saving TX: %w", &pgconn.PgError{Code: "23505"}),
+			want: "23505",
+		},
+		{
+			name: "other error",
+			err:  errors.New("foo"),
+		},
+		{
+			name: "no error",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, postgres.ErrorCode(tt.err))
+		})
+	}
+}
+
+func TestOptionErrors(t *testing.T) {
+	// Arrange
+	errs := postgres.OptionErrors{
+		postgres.ErrNoHosts,
+		fmt.Errorf("%w: foo", postgres.ErrInvalidOption),
+	}
+
+	// Assert
+	require.EqualError(t, errs, "invalid adapter options: no database hosts; invalid option value: foo")
+	require.ErrorIs(t, errs, postgres.ErrNoHosts)
+	require.ErrorIs(t, errs, postgres.ErrInvalidOption)
+	require.NotErrorIs(t, errs, postgres.ErrUnsupportedOption)
+}
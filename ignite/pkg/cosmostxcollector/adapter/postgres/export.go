@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// ndjsonFlushSize defines the number of exported transactions to buffer before writing them.
+const ndjsonFlushSize = 100
+
+const tplCopyToCSVSQL = "COPY (%s) TO STDOUT WITH CSV HEADER"
+
+// ErrUnknownTable is returned when a table can't be exported.
+var ErrUnknownTable = errors.New("unknown table")
+
+// csvTables contains the queries to select the rows of the tables that can be exported as CSV.
+var csvTables = map[string]string{
+	"tx":        "SELECT * FROM tx ORDER BY height, index",
+	"attribute": "SELECT * FROM attribute ORDER BY event_id, name",
+}
+
+// ExportNDJSON writes the transactions within a block height range as newline delimited JSON.
+// Each line contains a JSON encoded transaction, and transactions are written ordered by block
+// height and transaction index. The range includes both the "from" and "to" block heights.
+// Transactions are streamed to the writer so the whole range is never kept in memory.
+// It returns the number of exported transactions.
+func (a Adapter) ExportNDJSON(ctx context.Context, from, to int64, w io.Writer) (count int, err error) {
+	if err := validateHeightRange(from, to); err != nil {
+		return 0, err
+	}
+
+	defer func() { a.recordStatus(err) }()
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := selectTXs(ctx, db, sqlTXsByHeightRangeClauses, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	var (
+		buf = bufio.NewWriter(w)
+		enc = json.NewEncoder(buf)
+	)
+
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+
+		tx, err := scanTX(rows)
+		if err != nil {
+			return count, err
+		}
+
+		// The encoder terminates each encoded transaction with a newline
+		if err := enc.Encode(tx); err != nil {
+			return count, fmt.Errorf("failed to encode TX %s: %w", tx.Raw.Hash, err)
+		}
+
+		count++
+
+		// Write the buffered transactions periodically
+		if count%ndjsonFlushSize == 0 {
+			if err := buf.Flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+
+	return count, buf.Flush()
+}
+
+// ExportCSV writes the rows of a table as CSV, with a header that contains the column names.
+// It requires the pgx driver, configured with WithPgxDriver, and returns ErrUnsupportedOption
+// with the default driver. Only the "tx" and "attribute" tables can be exported, and the rows
+// are streamed to the writer using COPY. It returns the number of exported rows.
+func (a Adapter) ExportCSV(ctx context.Context, table string, w io.Writer) (count int64, err error) {
+	query, ok := csvTables[table]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownTable, table)
+	}
+
+	defer func() { a.recordStatus(err) }()
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return 0, err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer conn.Close()
+
+	err = conn.Raw(func(dc any) error {
+		pgConn, prefix := getCopyConn(dc)
+		if pgConn == nil {
+			return fmt.Errorf("%w: CSV export requires the pgx driver", ErrUnsupportedOption)
+		}
+
+		tag, err := pgConn.CopyTo(ctx, w, prefix+fmt.Sprintf(tplCopyToCSVSQL, query))
+		if err != nil {
+			return err
+		}
+
+		count = tag.RowsAffected()
+
+		return nil
+	})
+
+	return count, err
+}
+
+// getCopyConn returns the PostgreSQL connection of a driver connection when it supports COPY.
+// It also returns the query comment that must be added to the queries run with the connection.
+func getCopyConn(dc any) (_ *pgconn.PgConn, prefix string) {
+	if c, ok := dc.(commentConn); ok {
+		dc, prefix = c.Conn, c.prefix
+	}
+
+	if c, ok := dc.(*stdlib.Conn); ok {
+		return c.Conn().PgConn(), prefix
+	}
+
+	return nil, ""
+}
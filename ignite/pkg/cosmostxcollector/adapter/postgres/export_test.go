@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+func TestExportNDJSON(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+	ctx := context.Background()
+	tx := createTestTX(t)
+	tx.BlockTime = time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC)
+
+	// Arrange: JSON of the raw transaction result
+	jsonResTX, err := json.Marshal(tx.Raw)
+	require.NoError(t, err)
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(fmt.Sprintf(tplSelectTXsSQL, sqlTXsByHeightRangeClauses)).
+		WithArgs(int64(1), int64(10)).
+		WillReturnRows(
+			sqlmock.
+				NewRows([]string{"block_time", "data"}).
+				AddRow(tx.BlockTime, jsonResTX).
+				AddRow(tx.BlockTime, jsonResTX),
+		)
+
+	var buf bytes.Buffer
+
+	// Act
+	count, err := adapter.ExportNDJSON(ctx, 1, 10, &buf)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	var lines int
+
+	s := bufio.NewScanner(&buf)
+	for s.Scan() {
+		var got cosmosclient.TX
+
+		require.NoError(t, json.Unmarshal(s.Bytes(), &got))
+		require.Equal(t, tx.Raw.Hash, got.Raw.Hash)
+		require.True(t, tx.BlockTime.Equal(got.BlockTime))
+
+		lines++
+	}
+
+	require.Equal(t, count, lines)
+}
+
+func TestExportNDJSONInvalidRange(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+
+	// Act
+	_, err := adapter.ExportNDJSON(context.Background(), 10, 1, &bytes.Buffer{})
+
+	// Assert
+	require.ErrorIs(t, err, ErrInvalidHeightRange)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportCSVUnsupportedDriver(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+
+	// Act: The mock driver doesn't support COPY
+	_, err := adapter.ExportCSV(context.Background(), "tx", &bytes.Buffer{})
+
+	// Assert
+	require.ErrorIs(t, err, ErrUnsupportedOption)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportCSVUnknownTable(t *testing.T) {
+	// Arrange
+	adapter := Adapter{}
+
+	// Act
+	for _, table := range []string{"raw_tx", "event"} {
+		_, err := adapter.ExportCSV(context.Background(), table, &bytes.Buffer{})
+
+		// Assert
+		require.ErrorIs(t, err, ErrUnknownTable)
+	}
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+// WithPreCommit configures a function to call before the saved transactions are committed.
+func WithPreCommit(fn func(ctx context.Context, txs []cosmosclient.TX) error) Option {
+	return func(a *Adapter) {
+		a.preCommit = fn
+	}
+}
+
+// WithPostCommit configures a function to call after the saved transactions are committed.
+func WithPostCommit(fn func(ctx context.Context, txs []cosmosclient.TX)) Option {
+	return func(a *Adapter) {
+		a.postCommit = fn
+	}
+}
+
+// runPreCommit calls the pre commit function when there is one.
+func (a Adapter) runPreCommit(ctx context.Context, txs []cosmosclient.TX) error {
+	if a.preCommit == nil {
+		return nil
+	}
+
+	return a.preCommit(ctx, txs)
+}
+
+// runPostCommit calls the post commit function when there is one.
+func (a Adapter) runPostCommit(ctx context.Context, txs []cosmosclient.TX) {
+	if a.postCommit != nil {
+		a.postCommit(ctx, txs)
+	}
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+func TestSaveCommitHooks(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	var preTXs, postTXs []cosmosclient.TX
+
+	tx := createTestTX(t)
+	adapter := mustWith(t, Adapter{db: db},
+		WithPreCommit(func(_ context.Context, txs []cosmosclient.TX) error {
+			preTXs = txs
+			return nil
+		}),
+		WithPostCommit(func(_ context.Context, txs []cosmosclient.TX) {
+			postTXs = txs
+		}),
+	)
+
+	// Arrange: Database mock and expectations
+	expectSaveTX(mock, true)
+
+	// Act
+	err := adapter.Save(context.Background(), []cosmosclient.TX{tx})
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, []cosmosclient.TX{tx}, preTXs)
+	require.Equal(t, []cosmosclient.TX{tx}, postTXs)
+}
+
+func TestSavePreCommitError(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	wantErr := errors.New("pre commit failed")
+	postCommitCalled := false
+
+	adapter := mustWith(t, Adapter{db: db},
+		WithPreCommit(func(context.Context, []cosmosclient.TX) error {
+			return wantErr
+		}),
+		WithPostCommit(func(context.Context, []cosmosclient.TX) {
+			postCommitCalled = true
+		}),
+	)
+
+	// Arrange: Database mock and expectations
+	insertResult := sqlmock.NewResult(0, 1)
+
+	mock.ExpectBegin()
+	txStmt := mock.ExpectPrepare(sqlInsertTX)
+	evtStmt := mock.ExpectPrepare(sqlInsertEvent)
+	attrStmt := mock.ExpectPrepare(sqlInsertEventAttr)
+	mock.ExpectExec(sqlInsertRawTX).WillReturnResult(insertResult)
+	txStmt.ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	evtStmt.ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"event_id"}).AddRow(1))
+	attrStmt.ExpectExec().WillReturnResult(insertResult)
+	mock.ExpectRollback()
+
+	// Act
+	err := adapter.Save(context.Background(), []cosmosclient.TX{createTestTX(t)})
+
+	// Assert
+	require.ErrorIs(t, err, wantErr)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.False(t, postCommitCalled)
+}
+
+func TestSaveOneCommitHooks(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	var preCalled, postCalled bool
+
+	adapter := mustWith(t, Adapter{db: db},
+		WithPreCommit(func(context.Context, []cosmosclient.TX) error {
+			preCalled = true
+			return nil
+		}),
+		WithPostCommit(func(context.Context, []cosmosclient.TX) {
+			postCalled = true
+		}),
+	)
+
+	// Arrange: Database mock and expectations
+	expectSaveTX(mock, false)
+
+	// Act
+	err := adapter.SaveOne(context.Background(), createTestTX(t))
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.True(t, preCalled)
+	require.True(t, postCalled)
+}
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+const (
+	sqlSelectFingerprint = `
+		SELECT md5(concat_ws('|',
+			$3::text,
+			(
+				SELECT string_agg(table_name::text || '.' || column_name::text || ' ' || data_type::text, ',' ORDER BY table_name, ordinal_position)
+				FROM information_schema.columns
+				WHERE table_schema = current_schema() AND table_name = ANY($4)
+			),
+			(
+				SELECT COALESCE(string_agg(hash, ',' ORDER BY height, index), '')
+				FROM tx
+				WHERE height BETWEEN $1 AND $2
+			)
+		))
+	`
+	sqlSelectBlockIndexes = `
+		SELECT COUNT(*), COUNT(DISTINCT index), COALESCE(MIN(index), 0), COALESCE(MAX(index), -1)
+		FROM tx
+		WHERE height = $1
+	`
+	sqlSelectTXsWithoutAttrs = `
+		SELECT tx.hash
+		FROM tx
+			LEFT JOIN event ON tx.hash = event.tx_hash
+			LEFT JOIN attribute ON event.id = attribute.event_id
+		WHERE tx.height BETWEEN $1 AND $2
+		GROUP BY tx.hash, tx.height, tx.index
+		HAVING COUNT(attribute.event_id) = 0
+		ORDER BY tx.height, tx.index
+	`
+)
+
+// Fingerprint returns a hash of the transactions saved within a block height range.
+// The fingerprint is calculated using the schema version, the column definitions of the
+// adapter tables and the hashes of the transactions sorted by block height and index,
+// so databases with the same schema that saved the same transactions within the range
+// have the same fingerprint. The range includes both the "from" and "to" block heights.
+func (a Adapter) Fingerprint(ctx context.Context, from, to int64) (fingerprint string, err error) {
+	if err := validateHeightRange(from, to); err != nil {
+		return "", err
+	}
+
+	defer func() { a.recordStatus(err) }()
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return "", err
+	}
+
+	version, err := getSchemaVersion(ctx, db, a.schemas)
+	if err != nil {
+		return "", err
+	}
+
+	row := db.QueryRowContext(ctx, sqlSelectFingerprint, from, to, version, pq.Array(managedTables))
+	if err := row.Scan(&fingerprint); err != nil {
+		return "", fmt.Errorf("failed to calculate fingerprint: %w", err)
+	}
+
+	return fingerprint, nil
+}
+
+// VerifyBlockIntegrity checks that the transactions saved for a block height are complete.
+// A block is complete when the indexes of its transactions are a sequence that starts
+// at zero and doesn't have gaps or duplicates. Blocks without transactions are complete.
+func (a Adapter) VerifyBlockIntegrity(ctx context.Context, height int64) (ok bool, err error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return false, err
+	}
+
+	var count, distinct, minIndex, maxIndex int64
+
+	err = a.withRetry(ctx, func() error {
+		return db.QueryRowContext(ctx, sqlSelectBlockIndexes, height).Scan(&count, &distinct, &minIndex, &maxIndex)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to read transaction indexes of block %d: %w", height, err)
+	}
+
+	return count == distinct && minIndex == 0 && maxIndex == count-1, nil
+}
+
+// TXsWithoutAttributes returns the hashes of the transactions without event attributes within a block height range.
+// Transactions can have no events, but transactions without attributes might also be caused
+// by event attributes that were not saved, so it allows checking that no attributes were lost.
+// Hashes are sorted by block height and transaction index.
+// The range includes both the "from" and "to" block heights.
+func (a Adapter) TXsWithoutAttributes(ctx context.Context, from, to int64) (hashes []string, err error) {
+	if err := validateHeightRange(from, to); err != nil {
+		return nil, err
+	}
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.withRetry(ctx, func() (err error) {
+		hashes, err = fetchTXsWithoutAttributes(ctx, db, from, to)
+		return err
+	})
+
+	return hashes, err
+}
+
+// fetchTXsWithoutAttributes selects the hashes of the transactions without event attributes without retrying.
+func fetchTXsWithoutAttributes(ctx context.Context, db querier, from, to int64) (hashes []string, err error) {
+	rows, err := db.QueryContext(ctx, sqlSelectTXsWithoutAttrs, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transactions without attributes: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+
+		hashes = append(hashes, hash)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
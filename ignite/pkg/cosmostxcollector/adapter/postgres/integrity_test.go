@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	schemas := NewSchemas(fsSchemas, "")
+	adapter := Adapter{schemaOptions: schemaOptions{schemas: schemas}, db: db}
+	want := "d41d8cd98f00b204e9800998ecf8427e"
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectTableExists).
+		WithArgs(schemas.tableName).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.
+		ExpectQuery(schemas.GetSchemaVersionSQL()).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+	mock.
+		ExpectQuery(sqlSelectFingerprint).
+		WithArgs(int64(1), int64(10), uint64(5), pq.Array(managedTables)).
+		WillReturnRows(sqlmock.NewRows([]string{"fingerprint"}).AddRow(want))
+
+	// Act
+	fingerprint, err := adapter.Fingerprint(context.Background(), 1, 10)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, want, fingerprint)
+}
+
+func TestFingerprintInvalidRange(t *testing.T) {
+	_, err := Adapter{}.Fingerprint(context.Background(), 10, 1)
+
+	require.ErrorIs(t, err, ErrInvalidHeightRange)
+}
+
+func TestVerifyBlockIntegrity(t *testing.T) {
+	cases := []struct {
+		name                      string
+		count, distinct, min, max int64
+		want                      bool
+	}{
+		{
+			name:  "complete",
+			count: 3, distinct: 3, min: 0, max: 2,
+			want: true,
+		},
+		{
+			name: "without transactions",
+			max:  -1,
+			want: true,
+		},
+		{
+			name:  "with gap",
+			count: 2, distinct: 2, min: 0, max: 2,
+		},
+		{
+			name:  "with duplicate",
+			count: 3, distinct: 2, min: 0, max: 2,
+		},
+		{
+			name:  "without first transaction",
+			count: 2, distinct: 2, min: 1, max: 2,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			db, mock := createMatchEqualSQLMock(t)
+			defer db.Close()
+
+			adapter := Adapter{db: db}
+
+			// Arrange: Database mock and expectations
+			mock.
+				ExpectQuery(sqlSelectBlockIndexes).
+				WithArgs(int64(1)).
+				WillReturnRows(
+					sqlmock.NewRows([]string{"count", "distinct", "min", "max"}).
+						AddRow(tt.count, tt.distinct, tt.min, tt.max),
+				)
+
+			// Act
+			ok, err := adapter.VerifyBlockIntegrity(context.Background(), 1)
+
+			// Assert
+			require.NoError(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+			require.Equal(t, tt.want, ok)
+		})
+	}
+}
+
+func TestTXsWithoutAttributes(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+	want := []string{"F2564C78", "A1E78F25"}
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectTXsWithoutAttrs).
+		WithArgs(int64(1), int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow(want[0]).AddRow(want[1]))
+
+	// Act
+	hashes, err := adapter.TXsWithoutAttributes(context.Background(), 1, 10)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, want, hashes)
+}
+
+func TestTXsWithoutAttributesInvalidRange(t *testing.T) {
+	_, err := Adapter{}.TXsWithoutAttributes(context.Background(), 10, 1)
+
+	require.ErrorIs(t, err, ErrInvalidHeightRange)
+}
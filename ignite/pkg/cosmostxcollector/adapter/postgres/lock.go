@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// schemaLockRetryInterval defines the time to wait between schema lock attempts.
+const schemaLockRetryInterval = 100 * time.Millisecond
+
+const (
+	sqlSchemaLock    = "SELECT pg_advisory_lock(hashtext($1))"
+	sqlSchemaTryLock = "SELECT pg_try_advisory_lock(hashtext($1))"
+	sqlSchemaUnlock  = "SELECT pg_advisory_unlock(hashtext($1))"
+
+	tplSetLockTimeoutSQL = "SET lock_timeout = %d"
+	sqlResetLockTimeout  = "RESET lock_timeout"
+
+	// errCodeLockNotAvailable is the SQLSTATE code returned when a lock can't be acquired in time.
+	errCodeLockNotAvailable = "55P03"
+)
+
+// ErrMigrationLockTimeout is returned when the schema migration lock can't be acquired in time.
+var ErrMigrationLockTimeout = errors.New("timeout acquiring the schema migration lock")
+
+// ErrLockTimeout is returned when a schema update can't acquire a table lock in time.
+var ErrLockTimeout = errors.New("timeout acquiring a table lock")
+
+// WithMigrationLockTimeout configures the maximum time to wait for the schema migration lock.
+func WithMigrationLockTimeout(d time.Duration) Option {
+	return func(a *Adapter) {
+		a.migrationLockTimeout = d
+	}
+}
+
+// WithLockTimeout configures the maximum time that schema updates wait to acquire table locks.
+func WithLockTimeout(d time.Duration) Option {
+	return func(a *Adapter) {
+		a.lockTimeout = d
+	}
+}
+
+// setLockTimeout sets the lock timeout for the database session of a connection.
+// The returned function restores the default lock timeout of the session.
+func (a Adapter) setLockTimeout(ctx context.Context, conn *sql.Conn) (reset func(), err error) {
+	if a.lockTimeout <= 0 {
+		return func() {}, nil
+	}
+
+	// Parameters can't be used with SET so the timeout is added to the statement
+	query := fmt.Sprintf(tplSetLockTimeoutSQL, a.lockTimeout.Milliseconds())
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return nil, fmt.Errorf("failed to set lock timeout: %w", err)
+	}
+
+	reset = func() {
+		if _, err := conn.ExecContext(context.Background(), sqlResetLockTimeout); err != nil {
+			// Discard the connection so the pool doesn't reuse a session with the timeout
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}
+
+	return reset, nil
+}
+
+// lockTimeoutError is returned when a statement can't acquire a table lock in time.
+type lockTimeoutError struct {
+	timeout time.Duration
+	err     error
+}
+
+func (e lockTimeoutError) Error() string {
+	return fmt.Sprintf("%s after %s: %v", ErrLockTimeout, e.timeout, e.err)
+}
+
+func (e lockTimeoutError) Is(target error) bool {
+	return target == ErrLockTimeout
+}
+
+func (e lockTimeoutError) Unwrap() error {
+	return e.err
+}
+
+// wrapLockTimeout wraps the errors returned when a table lock is not acquired in time.
+func (a Adapter) wrapLockTimeout(err error) error {
+	if a.lockTimeout > 0 && ErrorCode(err) == errCodeLockNotAvailable {
+		return lockTimeoutError{a.lockTimeout, err}
+	}
+
+	return err
+}
+
+// lockSchema acquires the migration lock for a schema using a database advisory lock.
+// The lock is held by the database session of the connection until the returned
+// function is called to release it.
+func (a Adapter) lockSchema(ctx context.Context, conn *sql.Conn, s Schemas) (unlock func(), err error) {
+	if a.migrationLockTimeout > 0 {
+		err = tryLockSchema(ctx, conn, s, a.migrationLockTimeout)
+	} else if _, err = conn.ExecContext(ctx, sqlSchemaLock, s.tableName); err != nil {
+		err = fmt.Errorf("failed to acquire schema migration lock: %w", err)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	unlock = func() {
+		if _, err := conn.ExecContext(context.Background(), sqlSchemaUnlock, s.tableName); err != nil {
+			// Discard the connection when the lock can't be released so the
+			// database session is closed, which also releases the lock
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}
+
+	return unlock, nil
+}
+
+func tryLockSchema(ctx context.Context, conn *sql.Conn, s Schemas, timeout time.Duration) error {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		var locked bool
+		if err := conn.QueryRowContext(ctx, sqlSchemaTryLock, s.tableName).Scan(&locked); err != nil {
+			return fmt.Errorf("failed to acquire schema migration lock: %w", err)
+		}
+
+		if locked {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return fmt.Errorf("%w: %s", ErrMigrationLockTimeout, timeout)
+		case <-time.After(schemaLockRetryInterval):
+		}
+	}
+}
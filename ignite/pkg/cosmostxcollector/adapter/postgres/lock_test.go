@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateSchemaWithMigrationLockTimeout(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	s := NewSchemas(fsSchemas, "")
+	adapter := Adapter{schemaOptions: schemaOptions{schemas: s, migrationLockTimeout: time.Millisecond}, db: db}
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSchemaTryLock).
+		WithArgs(s.tableName).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(false))
+
+	// Act
+	err := adapter.UpdateSchema(context.Background(), s)
+
+	// Assert
+	require.ErrorIs(t, err, ErrMigrationLockTimeout)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLockSchemaWithTimeout(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	s := NewSchemas(fsSchemas, "")
+	adapter := Adapter{schemaOptions: schemaOptions{migrationLockTimeout: time.Minute}, db: db}
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSchemaTryLock).
+		WithArgs(s.tableName).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(false))
+	mock.
+		ExpectQuery(sqlSchemaTryLock).
+		WithArgs(s.tableName).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+	expectSchemaUnlock(mock, s)
+
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	// Act
+	unlock, err := adapter.lockSchema(ctx, conn, s)
+	require.NoError(t, err)
+
+	unlock()
+
+	// Assert
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateSchemaWithLockTimeout(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	tplSchemaScript := `BEGIN;
+		INSERT INTO schema(version)
+		VALUES(%d)
+	;%sCOMMIT;`
+	script := "ALTER TABLE tx ADD COLUMN foo TEXT;"
+	fs := fstest.MapFS{
+		"schemas/1.sql": &fstest.MapFile{Data: []byte(script)},
+	}
+	s := NewSchemas(fs, "")
+	adapter := Adapter{schemaOptions: schemaOptions{schemas: s, lockTimeout: 2 * time.Second}, db: db}
+
+	// Arrange: Database mock and expectations
+	expectSchemaLock(mock, s)
+	mock.
+		ExpectExec(fmt.Sprintf(tplSetLockTimeoutSQL, 2000)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectExec(s.GetTableDDL()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectQuery(s.GetSchemaVersionSQL()).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(uint64(0)))
+	mock.
+		ExpectExec(fmt.Sprintf(tplSchemaScript, 1, script)).
+		WillReturnError(&pq.Error{Code: errCodeLockNotAvailable})
+	mock.
+		ExpectExec(sqlResetLockTimeout).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	expectSchemaUnlock(mock, s)
+
+	// Act
+	err := adapter.UpdateSchema(context.Background(), s)
+
+	// Assert
+	require.ErrorIs(t, err, ErrLockTimeout)
+	require.ErrorAs(t, err, &SchemaError{})
+	require.Equal(t, errCodeLockNotAvailable, ErrorCode(err))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
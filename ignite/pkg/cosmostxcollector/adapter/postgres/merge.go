@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// mergeMinServerVersion is the first PostgreSQL server version that supports MERGE.
+const mergeMinServerVersion = 150000
+
+const (
+	sqlMergeTX = `
+		MERGE INTO tx USING (SELECT $1::CHAR(64) AS hash) AS src ON tx.hash = src.hash
+		WHEN MATCHED THEN
+			UPDATE SET index = $2, height = $3, block_time = $4, block_time_ms = $5,
+				code = $6, codespace = $7, chain_id = $8, raw_log = $10
+		WHEN NOT MATCHED THEN
+			INSERT (hash, index, height, block_time, block_time_ms, code, codespace, chain_id, created_at, raw_log)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	sqlSelectTXID = `
+		SELECT id FROM tx WHERE hash = $1
+	`
+)
+
+// WithMerge configures the adapter to update the existing transactions using MERGE when supported.
+func WithMerge() Option {
+	return func(a *Adapter) {
+		a.merge = true
+	}
+}
+
+// useMerge checks if the existing transactions must be updated using MERGE.
+func (a Adapter) useMerge(ctx context.Context) (bool, error) {
+	if !a.merge || a.txConflictPolicy != ConflictUpdate {
+		return false, nil
+	}
+
+	v, err := a.cachedServerVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return v >= mergeMinServerVersion, nil
+}
+
+// cachedServerVersion returns the version number of the server and keeps it for the next calls.
+func (a Adapter) cachedServerVersion(ctx context.Context) (int, error) {
+	if a.serverVersion == nil {
+		return a.ServerVersion(ctx)
+	}
+
+	a.serverVersion.mu.Lock()
+	defer a.serverVersion.mu.Unlock()
+
+	// The version is read again after errors
+	if a.serverVersion.version == 0 {
+		v, err := a.ServerVersion(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		a.serverVersion.version = v
+	}
+
+	return a.serverVersion.version, nil
+}
+
+// serverVersion keeps the version number of the database server.
+type serverVersion struct {
+	mu      sync.Mutex
+	version int
+}
+
+// mergeStmt merges transactions using MERGE.
+// MERGE can't return the ID of the merged transactions so it is selected after merging them.
+type mergeStmt struct {
+	stmt
+
+	tx *sql.Tx
+}
+
+// insertTX inserts a transaction using an insert statement and returns its ID.
+func insertTX(ctx context.Context, s stmt, args []any) (id int64, err error) {
+	m, ok := s.(mergeStmt)
+	if !ok {
+		err = s.QueryRowContext(ctx, args...).Scan(&id)
+		return id, err
+	}
+
+	if _, err := m.ExecContext(ctx, args...); err != nil {
+		return 0, err
+	}
+
+	err = m.tx.QueryRowContext(ctx, sqlSelectTXID, args[0]).Scan(&id)
+
+	return id, err
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+func TestSaveWithMerge(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := mustWith(t, Adapter{db: db, serverVersion: &serverVersion{}},
+		WithTXConflictPolicy(ConflictUpdate),
+		WithMerge(),
+	)
+	tx := createTestTX(t)
+	hash := tx.Raw.Hash.String()
+	insertResult := sqlmock.NewResult(0, 1)
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlShowServerVersion).
+		WillReturnRows(sqlmock.NewRows([]string{"server_version_num"}).AddRow(150002))
+
+	// Arrange: The server version is only read before the first save
+	for i := 0; i < 2; i++ {
+		mock.ExpectBegin()
+		txStmt := mock.ExpectPrepare(sqlMergeTX)
+		evtStmt := mock.ExpectPrepare(sqlInsertEvent)
+		attrStmt := mock.ExpectPrepare(sqlInsertEventAttr)
+		mock.ExpectExec(sqlInsertRawTX + sqlRawTXConflictUpdate).WillReturnResult(insertResult)
+		mock.ExpectExec(sqlDeleteTXEvents).WithArgs(hash).WillReturnResult(insertResult)
+		txStmt.ExpectExec().WillReturnResult(insertResult)
+		mock.ExpectQuery(sqlSelectTXID).WithArgs(hash).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		evtStmt.ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"event_id"}).AddRow(1))
+		attrStmt.ExpectExec().WillReturnResult(insertResult)
+		mock.ExpectCommit()
+	}
+
+	// Act
+	r, err := adapter.SaveWithResult(context.Background(), []cosmosclient.TX{tx})
+	require.NoError(t, err)
+
+	err = adapter.Save(context.Background(), []cosmosclient.TX{tx})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, []int64{7}, r.TXIDs)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveWithMergeUnsupported(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := mustWith(t, Adapter{db: db},
+		WithTXConflictPolicy(ConflictUpdate),
+		WithMerge(),
+	)
+	insertResult := sqlmock.NewResult(0, 1)
+
+	// Arrange: Servers older than version 15 use ON CONFLICT
+	mock.
+		ExpectQuery(sqlShowServerVersion).
+		WillReturnRows(sqlmock.NewRows([]string{"server_version_num"}).AddRow(140007))
+	mock.ExpectBegin()
+	mock.ExpectExec(sqlInsertRawTX + sqlRawTXConflictUpdate).WillReturnResult(insertResult)
+	mock.ExpectExec(sqlDeleteTXEvents).WillReturnResult(insertResult)
+	mock.ExpectQuery(sqlUpsertTX).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(sqlInsertEvent).WillReturnRows(sqlmock.NewRows([]string{"event_id"}).AddRow(1))
+	mock.ExpectExec(sqlInsertEventAttr).WillReturnResult(insertResult)
+	mock.ExpectCommit()
+
+	// Act
+	err := adapter.SaveOne(context.Background(), createTestTX(t))
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
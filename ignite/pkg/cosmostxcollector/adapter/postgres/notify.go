@@ -0,0 +1,262 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+const (
+	// NotifyChannelTX defines the name of the channel used to notify saved transactions.
+	NotifyChannelTX = "cosmostxcollector_tx"
+
+	// NotifyChannelSchema defines the name of the channel used to notify schema updates.
+	NotifyChannelSchema = "cosmostxcollector_schema"
+
+	listenerMinReconnectInterval = 10 * time.Second
+	listenerMaxReconnectInterval = time.Minute
+	listenerPingInterval         = 90 * time.Second
+
+	sqlNotifyTXs = `
+		SELECT pg_notify($1, hash)
+		FROM unnest($2::text[]) AS hash
+	`
+	sqlNotifySchema = `
+		SELECT pg_notify($1, $2)
+	`
+)
+
+// WithSchemaNotify enables notifications for the schema updates.
+func WithSchemaNotify() Option {
+	return func(a *Adapter) {
+		a.schemaNotify = true
+	}
+}
+
+// TXNotification defines a notification for a saved transaction.
+type TXNotification struct {
+	// Hash is the hash of the saved transaction.
+	Hash string
+}
+
+// Subscribe listens for notifications of saved transactions.
+// Notifications are only sent by adapters that save transactions using the
+// WithNotifications option, and they are sent after the transactions are committed.
+// The listener reconnects automatically when the database connection is lost,
+// though notifications sent while the listener was disconnected are lost.
+// Notifications are received using pgx when the adapter uses the pgx driver.
+// The returned channel is closed when the context is done.
+func (a Adapter) Subscribe(ctx context.Context) (<-chan TXNotification, error) {
+	if _, err := a.getDB(); err != nil {
+		return nil, err
+	}
+
+	listen := listenPq
+	if a.driverName == PgxDriverName {
+		listen = listenPgx
+	}
+
+	payloads, err := listen(ctx, a, NotifyChannelTX)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for TX notifications: %w", err)
+	}
+
+	nc := make(chan TXNotification)
+
+	go func() {
+		defer close(nc)
+
+		for p := range payloads {
+			select {
+			case nc <- TXNotification{Hash: p}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nc, nil
+}
+
+// listenPq listens for the notifications of a channel using the default driver.
+// The returned channel receives the notification payloads and it is closed when the context is done.
+func listenPq(ctx context.Context, a Adapter, channel string) (<-chan string, error) {
+	// The listener uses the dialer of the adapter when there is one
+	var d pq.Dialer = pqDialer(defaultDialer.DialContext)
+	if dial := a.dialFunc(); dial != nil {
+		d = pqDialer(dial)
+	}
+
+	l := pq.NewDialListener(d, createPostgresURI(a), listenerMinReconnectInterval, listenerMaxReconnectInterval, nil)
+
+	// Listen blocks until the listener is connected, so it is stopped by closing the listener
+	errc := make(chan error, 1)
+	go func() { errc <- l.Listen(channel) }()
+
+	select {
+	case err := <-errc:
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+	case <-ctx.Done():
+		l.Close()
+		return nil, ctx.Err()
+	}
+
+	pc := make(chan string)
+
+	go func() {
+		defer close(pc)
+		defer l.Close()
+
+		ticker := time.NewTicker(listenerPingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// Check the connection periodically to detect when it is lost
+				go l.Ping()
+			case n := <-l.Notify:
+				// A nil notification is received after the listener reconnects
+				if n == nil {
+					continue
+				}
+
+				select {
+				case pc <- n.Extra:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return pc, nil
+}
+
+// listenPgx listens for the notifications of a channel using pgx.
+// The returned channel receives the notification payloads and it is closed when the context is done.
+// The listener reconnects when the connection is lost, and it waits between the connection
+// attempts, doubling the time after each failed attempt up to a maximum.
+func listenPgx(ctx context.Context, a Adapter, channel string) (<-chan string, error) {
+	cfg, err := parsePgxConfig(createPostgresURI(a), a.dialFunc())
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := connectPgxListener(ctx, cfg, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := make(chan string)
+
+	go func() {
+		defer close(pc)
+		defer func() {
+			if conn != nil {
+				conn.Close(context.Background())
+			}
+		}()
+
+		for {
+			n, err := conn.WaitForNotification(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				conn.Close(context.Background())
+
+				if conn = reconnectPgxListener(ctx, cfg, channel); conn == nil {
+					return
+				}
+
+				continue
+			}
+
+			select {
+			case pc <- n.Payload:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return pc, nil
+}
+
+// connectPgxListener opens a pgx connection that listens for the notifications of a channel.
+func connectPgxListener(ctx context.Context, cfg *pgx.ConnConfig, channel string) (*pgx.Conn, error) {
+	conn, err := pgx.ConnectConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		conn.Close(context.Background())
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// reconnectPgxListener opens a new pgx listener connection after the previous one was lost.
+// Nil is returned when the context is done before the connection is opened.
+func reconnectPgxListener(ctx context.Context, cfg *pgx.ConnConfig, channel string) *pgx.Conn {
+	interval := listenerMinReconnectInterval
+
+	for {
+		t := time.NewTimer(interval)
+
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil
+		case <-t.C:
+		}
+
+		if conn, err := connectPgxListener(ctx, cfg, channel); err == nil {
+			return conn
+		}
+
+		if interval *= 2; interval > listenerMaxReconnectInterval {
+			interval = listenerMaxReconnectInterval
+		}
+	}
+}
+
+// notifyTXs sends a notification for each transaction.
+// Notifications are delivered when the database transaction is committed.
+func notifyTXs(ctx context.Context, sqlTx *sql.Tx, txs ...cosmosclient.TX) error {
+	hashes := make([]string, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Raw.Hash.String()
+	}
+
+	if _, err := sqlTx.ExecContext(ctx, sqlNotifyTXs, NotifyChannelTX, pq.Array(hashes)); err != nil {
+		return fmt.Errorf("error sending TX notifications: %w", err)
+	}
+
+	return nil
+}
+
+// notifySchema sends a notification with the version of an applied schema.
+func notifySchema(ctx context.Context, conn *sql.Conn, version uint64) error {
+	if _, err := conn.ExecContext(ctx, sqlNotifySchema, NotifyChannelSchema, strconv.FormatUint(version, 10)); err != nil {
+		return fmt.Errorf("error sending schema notification: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveOneWithNotifications(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{saveOptions: saveOptions{notify: true}, db: db}
+	ctx := context.Background()
+	tx := createTestTX(t)
+	insertResult := sqlmock.NewResult(0, 1)
+
+	// Arrange: Database mock and expectations
+	mock.ExpectBegin()
+	mock.ExpectExec(sqlInsertRawTX).WillReturnResult(insertResult)
+	mock.
+		ExpectQuery(sqlInsertTX).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.
+		ExpectQuery(sqlInsertEvent).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id"}).AddRow(1))
+	mock.ExpectExec(sqlInsertEventAttr).WillReturnResult(insertResult)
+	mock.
+		ExpectExec(sqlNotifyTXs).
+		WithArgs(NotifyChannelTX, pq.Array([]string{tx.Raw.Hash.String()})).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// Act
+	err := adapter.SaveOne(ctx, tx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateSchemaWithSchemaNotify(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	tplSchemaScript := `BEGIN;
+		INSERT INTO schema(version)
+		VALUES(%d)
+	;%sCOMMIT;`
+
+	fs := fstest.MapFS{
+		"schemas/1.sql": &fstest.MapFile{Data: []byte("/* V1 */")},
+		"schemas/2.sql": &fstest.MapFile{Data: []byte("/* V2 */")},
+	}
+	s := NewSchemas(fs, "")
+	adapter := mustWith(t, Adapter{schemaOptions: schemaOptions{schemas: s}, db: db}, WithSchemaNotify())
+
+	// Arrange: Database mock and expectations
+	expectSchemaLock(mock, s)
+	mock.
+		ExpectExec(s.GetTableDDL()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectQuery(s.GetSchemaVersionSQL()).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(uint64(0)))
+	mock.
+		ExpectExec(fmt.Sprintf(tplSchemaScript, 1, "/* V1 */")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectExec(fmt.Sprintf(tplSchemaScript, 2, "/* V2 */")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// The notification contains the latest applied schema version
+	mock.
+		ExpectExec(sqlNotifySchema).
+		WithArgs(NotifyChannelSchema, "2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	expectSchemaUnlock(mock, s)
+
+	// Act
+	err := adapter.UpdateSchema(context.Background(), s)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateSchemaWithSchemaNotifyUnchanged(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	fs := fstest.MapFS{
+		"schemas/1.sql": &fstest.MapFile{Data: []byte("/* V1 */")},
+	}
+	s := NewSchemas(fs, "")
+	adapter := mustWith(t, Adapter{schemaOptions: schemaOptions{schemas: s}, db: db}, WithSchemaNotify())
+
+	// Arrange: Database mock and expectations
+	expectSchemaLock(mock, s)
+	mock.
+		ExpectExec(s.GetTableDDL()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectQuery(s.GetSchemaVersionSQL()).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(uint64(1)))
+
+	// No notification is sent when the schema is already updated
+	expectSchemaUnlock(mock, s)
+
+	// Act
+	changed, err := adapter.UpdateSchemaChanged(context.Background(), s)
+
+	// Assert
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubscribeWithPgx(t *testing.T) {
+	// Arrange
+	var addrs []string
+
+	wantErr := errors.New("dial failed")
+	dial := func(_ context.Context, _, addr string) (net.Conn, error) {
+		addrs = append(addrs, addr)
+		return nil, wantErr
+	}
+
+	a, err := NewAdapter("test", WithPgxDriver(), WithHost("db"), WithPort(5433), WithDialer(dial))
+	require.NoError(t, err)
+
+	defer a.db.Close()
+
+	// Act: The pgx listener fails as soon as the connection can't be opened
+	_, err = a.Subscribe(context.Background())
+
+	// Assert
+	require.ErrorIs(t, err, wantErr)
+	require.Contains(t, addrs, "db:5433")
+}
+
+func TestSubscribeCanceled(t *testing.T) {
+	// Arrange
+	dial := func(context.Context, string, string) (net.Conn, error) {
+		return nil, errors.New("dial failed")
+	}
+
+	a, err := NewAdapter("test", WithDialer(dial))
+	require.NoError(t, err)
+
+	defer a.db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Act: The listener keeps trying to connect until the context is done
+	_, err = a.Subscribe(ctx)
+
+	// Assert
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,306 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// HostPort defines a database host address.
+type HostPort struct {
+	// Host is the name or IP address of the host.
+	Host string
+
+	// Port is the port of the host.
+	// The default port is used when the port is zero.
+	Port uint
+}
+
+// Option defines an option for the adapter.
+type Option func(*Adapter)
+
+// WithHost configures a database host name or IP.
+func WithHost(host string) Option {
+	return func(a *Adapter) {
+		a.host = host
+	}
+}
+
+// WithPort configures a database port.
+func WithPort(port uint) Option {
+	return func(a *Adapter) {
+		a.port = port
+	}
+}
+
+// WithHosts configures multiple database hosts to connect to, which requires a driver like pgx.
+func WithHosts(hosts ...HostPort) Option {
+	return func(a *Adapter) {
+		// The hosts are never nil so an empty list can be detected
+		a.hosts = append([]HostPort{}, hosts...)
+	}
+}
+
+// WithTargetSessionAttrs configures the type of session required when connecting to the database.
+func WithTargetSessionAttrs(attr string) Option {
+	return func(a *Adapter) {
+		a.targetSessionAttrs = attr
+	}
+}
+
+// WithUser configures a database user.
+func WithUser(user string) Option {
+	return func(a *Adapter) {
+		a.user = user
+	}
+}
+
+// WithPassword configures a database password.
+func WithPassword(password string) Option {
+	return func(a *Adapter) {
+		a.password = password
+	}
+}
+
+// WithParams configures extra database parameters.
+func WithParams(params map[string]string) Option {
+	return func(a *Adapter) {
+		a.params = params
+	}
+}
+
+// WithParam configures a single extra database parameter.
+func WithParam(key, value string) Option {
+	return func(a *Adapter) {
+		// Copy the parameters to avoid changing a map that is shared
+		params := make(map[string]string, len(a.params)+1)
+		for k, v := range a.params {
+			params[k] = v
+		}
+
+		params[key] = value
+		a.params = params
+	}
+}
+
+// WithDriverName configures the name of the SQL driver used to connect to the database.
+func WithDriverName(name string) Option {
+	return func(a *Adapter) {
+		a.driverName = name
+	}
+}
+
+// WithSearchPath configures the schema search path for the database connections.
+func WithSearchPath(schemas ...string) Option {
+	return func(a *Adapter) {
+		a.searchPath = schemas
+	}
+}
+
+// WithRole configures the role used by the database connections.
+func WithRole(role string) Option {
+	return func(a *Adapter) {
+		a.role = role
+	}
+}
+
+// WithNotifications enables notifications for the saved transactions.
+func WithNotifications() Option {
+	return func(a *Adapter) {
+		a.notify = true
+	}
+}
+
+// WithTypeHints enables saving the type of the event attribute values.
+func WithTypeHints() Option {
+	return func(a *Adapter) {
+		a.typeHints = true
+	}
+}
+
+// WithStrictValidation enables strict validation of the transactions being saved.
+func WithStrictValidation() Option {
+	return func(a *Adapter) {
+		a.strictValidation = true
+	}
+}
+
+// WithRequireExistingSchema requires the schema table to exist when the schema is updated.
+func WithRequireExistingSchema() Option {
+	return func(a *Adapter) {
+		a.requireSchema = true
+	}
+}
+
+// WithEpochTime enables saving the block time as a Unix epoch time in milliseconds.
+func WithEpochTime() Option {
+	return func(a *Adapter) {
+		a.epochTime = true
+	}
+}
+
+// WithChainID configures the ID of the chain of the saved transactions.
+func WithChainID(id string) Option {
+	return func(a *Adapter) {
+		a.chainID = id
+	}
+}
+
+// WithName configures a name to distinguish the adapter from other adapters.
+func WithName(name string) Option {
+	return func(a *Adapter) {
+		a.name = name
+	}
+}
+
+// WithSeparatePools configures different database connection pools for reads and writes.
+func WithSeparatePools() Option {
+	return func(a *Adapter) {
+		a.separatePools = true
+	}
+}
+
+// WithMaxBatchBytes configures the maximum size of the attribute values saved within a database transaction.
+func WithMaxBatchBytes(n int) Option {
+	return func(a *Adapter) {
+		a.maxBatchBytes = n
+	}
+}
+
+// WithIsolationLevel configures the isolation level of the database transactions.
+func WithIsolationLevel(level sql.IsolationLevel) Option {
+	return func(a *Adapter) {
+		a.isolationLevel = level
+	}
+}
+
+// WithContinueOnError configures the adapter to save each transaction separately and skip the failed ones.
+func WithContinueOnError() Option {
+	return func(a *Adapter) {
+		a.continueOnError = true
+	}
+}
+
+// WithTXSavepoints configures the adapter to skip the failed transactions of a batch using savepoints.
+func WithTXSavepoints() Option {
+	return func(a *Adapter) {
+		a.txSavepoints = true
+	}
+}
+
+// WithClock configures the function that returns the current time.
+func WithClock(fn func() time.Time) Option {
+	return func(a *Adapter) {
+		a.clock = fn
+	}
+}
+
+// WithConnMaxIdleTime configures the maximum amount of time a database connection may be idle.
+func WithConnMaxIdleTime(d time.Duration) Option {
+	return func(a *Adapter) {
+		a.connMaxIdleTime = d
+	}
+}
+
+// WithMaxOpenConns configures the maximum number of open database connections.
+func WithMaxOpenConns(n int) Option {
+	return func(a *Adapter) {
+		a.maxOpenConns = n
+	}
+}
+
+// WithMaxIdleConns configures the maximum number of idle database connections to keep open.
+func WithMaxIdleConns(n int) Option {
+	return func(a *Adapter) {
+		a.maxIdleConns = n
+	}
+}
+
+// validateOptions checks that the adapter options are valid.
+// All the options are checked and an OptionErrors is returned with the errors of all the invalid options.
+func (a Adapter) validateOptions() error {
+	var errs OptionErrors
+
+	if a.hosts != nil && len(a.hosts) == 0 {
+		errs = append(errs, ErrNoHosts)
+	}
+
+	errs.add(validateParam(paramTargetSessionAttrs, a.targetSessionAttrs, "any", "read-write", "read-only", "primary", "standby", "prefer-standby"))
+	errs.add(validateConflictPolicy(a.attrConflictPolicy))
+	errs.add(validateTXConflictPolicy(a.txConflictPolicy))
+	errs.add(validateQueryComment(a.queryComment))
+	errs.add(validateValueColumnType(a.valueColumnType))
+	errs.add(validateCreateDatabase(a.createDatabase))
+
+	if strings.IndexFunc(a.role, unicode.IsControl) != -1 {
+		errs = append(errs, fmt.Errorf("%w: role '%s' contains invalid characters", ErrInvalidOption, a.role))
+	}
+
+	for _, o := range []struct {
+		name  string
+		value int64
+	}{
+		{"batch size", int64(a.batchSize)},
+		{"maximum batch bytes", int64(a.maxBatchBytes)},
+		{"maximum open connections", int64(a.maxOpenConns)},
+		{"maximum idle connections", int64(a.maxIdleConns)},
+		{"connection max idle time", int64(a.connMaxIdleTime)},
+		{"flush interval", int64(a.flushInterval)},
+		{"write buffer capacity", int64(a.writeBufferCap)},
+		{"pool watcher interval", int64(a.poolWatcherInterval)},
+		{"auto analyze threshold", int64(a.autoAnalyzeThreshold)},
+		{"maximum retries", int64(a.retryPolicy.MaxRetries)},
+		{"migration lock timeout", int64(a.migrationLockTimeout)},
+		{"lock timeout", int64(a.lockTimeout)},
+	} {
+		if o.value < 0 {
+			errs = append(errs, fmt.Errorf("%w: %s can't be negative", ErrInvalidOption, o.name))
+		}
+	}
+
+	// The default driver sends unknown parameters to the server, which fails to connect
+	if a.driverName == DefaultDriverName {
+		if len(a.hosts) > 1 {
+			errs = append(errs, fmt.Errorf("%w: multiple hosts", ErrUnsupportedOption))
+		}
+
+		if a.targetSessionAttrs != "" {
+			errs = append(errs, fmt.Errorf("%w: target session attributes", ErrUnsupportedOption))
+		}
+	}
+
+	// Dialers and keepalives are only supported by the drivers that allow opening the network connections
+	if a.driverName != DefaultDriverName && a.driverName != PgxDriverName {
+		if a.dialer != nil {
+			errs = append(errs, fmt.Errorf("%w: dialer", ErrUnsupportedOption))
+		}
+
+		if a.keepAlive != 0 {
+			errs = append(errs, fmt.Errorf("%w: keepalives", ErrUnsupportedOption))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// validateParam checks that the value of a connection parameter is one of the allowed values.
+// Empty values are valid because they are not added to the connection parameters.
+func validateParam(name, value string, allowed ...string) error {
+	if value == "" {
+		return nil
+	}
+
+	for _, v := range allowed {
+		if value == v {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s '%s' must be one of %s", ErrInvalidOption, name, value, strings.Join(allowed, ", "))
+}
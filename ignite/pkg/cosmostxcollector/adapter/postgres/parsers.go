@@ -26,8 +26,8 @@ const (
 			SELECT event.id, event.index, event.tx_hash, event.type, event.created_at
 			FROM event
 				INNER JOIN tx ON event.tx_hash = tx.hash
-				INNER JOIN attribute ON event.id = attribute.event_id
-			%s
+				INNER JOIN %[2]s ON event.id = attribute.event_id
+			%[1]s
 			ORDER BY tx.height, tx.index, event.index
 		) AS events
 	`
@@ -68,25 +68,25 @@ func parseQuery(q query.Query) (string, error) {
 	return strings.Join(sections, " "), nil
 }
 
-func parseEventQuery(q query.EventQuery) string {
-	sql := tplSelectEventsSQL
+// parseEventQuery returns the SQL for an event query.
+// The attribute table SQL is used to join the attributes when the filters reference them.
+func parseEventQuery(q query.EventQuery, attrTable string) string {
 	filters := q.Filters()
+	sql := fmt.Sprintf(tplSelectEventsSQL, parseFilters(filters))
 
 	// Check if any of the filters references an event attribute
 	// and if so add the required INNER JOIN to the raw SQL query.
 	// The JOIN is not present by default to improve events queries.
 	for _, f := range filters {
 		if strings.HasPrefix(f.Field(), eventAttrPrefix) {
-			sql = tplSelectEventsWithAttrSQL
+			sql = fmt.Sprintf(tplSelectEventsWithAttrSQL, parseFilters(filters), attrTable)
 
 			break
 		}
 	}
 
 	// Add SELECT
-	sections := []string{
-		fmt.Sprintf(sql, parseFilters(q.Filters())),
-	}
+	sections := []string{sql}
 
 	// Add LIMIT/OFFSET
 	if s, ok := parsePaging(q); ok {
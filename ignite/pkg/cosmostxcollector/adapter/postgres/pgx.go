@@ -0,0 +1,14 @@
+package postgres
+
+import (
+	// Register the pgx driver with the SQL package
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// PgxDriverName is the name of the pgx SQL driver.
+const PgxDriverName = "pgx"
+
+// WithPgxDriver configures the adapter to use the pgx driver for queries and notifications.
+func WithPgxDriver() Option {
+	return WithDriverName(PgxDriverName)
+}
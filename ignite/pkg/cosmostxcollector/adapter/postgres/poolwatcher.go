@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// WithPoolWatcher configures a function to call periodically with the statistics of the connection pool.
+func WithPoolWatcher(fn func(stats sql.DBStats), interval time.Duration) Option {
+	return func(a *Adapter) {
+		a.poolWatcherFn = fn
+		a.poolWatcherInterval = interval
+	}
+}
+
+// poolWatcher calls a function periodically with the statistics of a connection pool.
+type poolWatcher struct {
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// startPoolWatcher starts watching a connection pool.
+func startPoolWatcher(db *sql.DB, fn func(sql.DBStats), interval time.Duration) *poolWatcher {
+	w := &poolWatcher{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(w.done)
+
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-t.C:
+				fn(db.Stats())
+			}
+		}
+	}()
+
+	return w
+}
+
+// close stops watching the connection pool and waits until the goroutine stops.
+func (w *poolWatcher) close() {
+	w.once.Do(func() { close(w.stop) })
+	<-w.done
+}
@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"database/sql"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolWatcher(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	db.SetMaxOpenConns(3)
+
+	var calls int32
+
+	adapter := Adapter{db: db}
+	adapter.poolWatcher = startPoolWatcher(db, func(stats sql.DBStats) {
+		if stats.MaxOpenConnections == 3 {
+			atomic.AddInt32(&calls, 1)
+		}
+	}, time.Millisecond)
+
+	mock.ExpectClose()
+
+	// Act
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) >= 2
+	}, time.Second, time.Millisecond)
+
+	err := adapter.Close()
+
+	// Assert: The function must not be called after closing the adapter
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	n := atomic.LoadInt32(&calls)
+	time.Sleep(5 * time.Millisecond)
+	require.Equal(t, n, atomic.LoadInt32(&calls))
+}
@@ -1,317 +1,631 @@
+// Package postgres implements a data backend adapter for PostgreSQL.
+// The adapter uses the "postgres" SQL driver by default. Some features, like
+// exporting tables as CSV with ExportCSV, require the pgx driver to be
+// configured using WithPgxDriver.
 package postgres
 
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"embed"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/lib/pq"
-	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/ignite/cli/ignite/pkg/cosmosclient"
-	"github.com/ignite/cli/ignite/pkg/cosmostxcollector/query"
 )
 
 const (
-	DefaultPort = 5432
-	DefaultHost = "127.0.0.1"
+	DefaultPort       = 5432
+	DefaultHost       = "127.0.0.1"
+	DefaultDriverName = "postgres"
+
+	// MaxRecentTXs defines the maximum number of recent transactions that can be read at once.
+	MaxRecentTXs = 1000
 )
 
 const (
 	adapterType = "postgres"
 
-	sqlSelectBlockHeight = `
-		SELECT COALESCE(MAX(height), 0)
-		FROM tx
-	`
-	sqlSelectEventAttrs = `
-		SELECT event_id, name, value FROM attribute
-		WHERE event_id = ANY($1)
-		ORDER BY event_id
-	`
-	sqlInsertTX = `
-		INSERT INTO tx (hash, index, height, block_time)
-		VALUES ($1, $2, $3, $4)
-	`
-	sqlInsertEvent = `
-		INSERT INTO event (tx_hash, type, index)
-		VALUES ($1, $2, $3) RETURNING id
-	`
-	sqlInsertEventAttr = `
-		INSERT INTO attribute (event_id, name, value)
-		VALUES ($1, $2, $3)
-	`
-	sqlInsertRawTX = `
-		INSERT INTO raw_tx (hash, data)
-		VALUES ($1, $2)
-	`
+	paramOptions            = "options"
+	paramSSLMode            = "sslmode"
+	paramTargetSessionAttrs = "target_session_attrs"
 )
 
 //go:embed schemas/*
 var fsSchemas embed.FS
 
-// ErrClosed is returned when database connection is not open.
-var ErrClosed = errors.New("no database connection")
+var (
+	// ErrClosed is returned when database connection is not open.
+	ErrClosed = errors.New("no database connection")
 
-// Option defines an option for the adapter.
-type Option func(*Adapter)
+	// ErrNotFound is returned when a transaction is not found.
+	ErrNotFound = errors.New("transaction not found")
 
-// WithHost configures a database host name or IP.
-func WithHost(host string) Option {
-	return func(a *Adapter) {
-		a.host = host
-	}
-}
+	// ErrInvalidHeightRange is returned when a block height range is not valid.
+	ErrInvalidHeightRange = errors.New("invalid block height range")
 
-// WithPort configures a database port.
-func WithPort(port uint) Option {
-	return func(a *Adapter) {
-		a.port = port
-	}
-}
+	// ErrInvalidBlockHeight is returned when a transaction doesn't belong to a block.
+	ErrInvalidBlockHeight = errors.New("invalid transaction block height")
 
-// WithUser configures a database user.
-func WithUser(user string) Option {
-	return func(a *Adapter) {
-		a.user = user
-	}
+	// ErrInvalidTimeRange is returned when a time range is not valid.
+	ErrInvalidTimeRange = errors.New("invalid time range")
+
+	// ErrInvalidTXHash is returned when a transaction hash is not valid.
+	ErrInvalidTXHash = errors.New("invalid transaction hash")
+
+	// ErrNoHosts is returned when the list of database hosts is empty.
+	ErrNoHosts = errors.New("no database hosts")
+
+	// ErrUnsupportedOption is returned when an option is not supported by the SQL driver.
+	ErrUnsupportedOption = errors.New("option not supported by the SQL driver")
+
+	// ErrInvalidOption is returned when an option has a value that is not valid.
+	ErrInvalidOption = errors.New("invalid option value")
+
+	// ErrDerivedAdapter is returned when closing an adapter that shares the database connection pool.
+	ErrDerivedAdapter = errors.New("adapter created with With can't be closed")
+
+	// ErrSchemaNotFound is returned when the schema table doesn't exist and an existing schema is required.
+	ErrSchemaNotFound = errors.New("schema table not found")
+)
+
+// ExpectedSchemaVersion returns the schema version required by the adapter.
+// It can be compared with the version applied to the database to know
+// if the database schema must be updated.
+func ExpectedSchemaVersion() uint {
+	// Reading the embedded schemas never fails
+	v, _ := NewSchemas(fsSchemas, "").LatestVersion()
+	return v
 }
 
-// WithPassword configures a database password.
-func WithPassword(password string) Option {
-	return func(a *Adapter) {
-		a.password = password
-	}
+// SchemaFiles returns the schema files embedded in the adapter sorted by version.
+// It allows reviewing the SQL scripts that are applied when the schema is updated.
+func SchemaFiles() ([]SchemaFile, error) {
+	return NewSchemas(fsSchemas, "").Files()
 }
 
-// WithParams configures extra database parameters.
-func WithParams(params map[string]string) Option {
-	return func(a *Adapter) {
-		a.params = params
-	}
+// DumpSchema returns the SQL scripts of the schema files embedded in the adapter as a single SQL script.
+// The scripts are sorted by version and create the attribute value column with the default type.
+func DumpSchema() (string, error) {
+	return NewSchemas(fsSchemas, "").Dump()
 }
 
 // NewAdapter creates a new PostgreSQL adapter.
+// An OptionErrors error with all the invalid options is returned when the options are not valid.
 func NewAdapter(database string, options ...Option) (Adapter, error) {
 	adapter := Adapter{
-		host:     DefaultHost,
-		port:     DefaultPort,
-		database: database,
-		schemas:  NewSchemas(fsSchemas, ""),
+		connOptions: connOptions{
+			host:       DefaultHost,
+			port:       DefaultPort,
+			database:   database,
+			driverName: DefaultDriverName,
+		},
+		schemaOptions: schemaOptions{
+			schemas: NewSchemas(fsSchemas, ""),
+		},
+		buffer:        &txBuffer{},
+		status:        &status{},
+		serverVersion: &serverVersion{},
 	}
 
 	for _, o := range options {
 		o(&adapter)
 	}
 
-	db, err := sql.Open("postgres", createPostgresURI(adapter))
+	if err := adapter.validateOptions(); err != nil {
+		return Adapter{}, err
+	}
+
+	if adapter.createDatabase {
+		if err := createDatabase(context.Background(), adapter); err != nil {
+			return Adapter{}, err
+		}
+	}
+
+	db, err := openDB(adapter)
 	if err != nil {
 		return Adapter{}, err
 	}
 
 	adapter.db = db
 
+	if adapter.separatePools {
+		if adapter.readDB, err = openDB(adapter); err != nil {
+			db.Close()
+			return Adapter{}, err
+		}
+	}
+
+	if adapter.writeBufferCap > 0 {
+		adapter.writes = newWriteBuffer(adapter.writeBufferCap)
+		go adapter.writes.run(adapter.saveUnbuffered, adapter.getBatchSize())
+	}
+
+	if adapter.poolWatcherFn != nil && adapter.poolWatcherInterval > 0 {
+		adapter.poolWatcher = startPoolWatcher(db, adapter.poolWatcherFn, adapter.poolWatcherInterval)
+	}
+
+	if adapter.autoAnalyzeThreshold > 0 {
+		adapter.analyzer = newAnalyzer()
+	}
+
 	return adapter, nil
 }
 
+// openDB opens a database connection pool.
+func openDB(a Adapter) (*sql.DB, error) {
+	db, err := openPool(a)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.connMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(a.connMaxIdleTime)
+	}
+
+	if a.maxOpenConns > 0 {
+		db.SetMaxOpenConns(a.maxOpenConns)
+	}
+
+	if a.maxIdleConns > 0 {
+		db.SetMaxIdleConns(a.maxIdleConns)
+	}
+
+	return db, nil
+}
+
+// openPool opens a database connection pool.
+// The connections are opened using the dialer when there is one, the query
+// comment is added to their queries, and the connection hooks are called
+// for each new connection.
+func openPool(a Adapter) (*sql.DB, error) {
+	uri := createPostgresURI(a)
+	dial := a.dialFunc()
+	if len(a.connHooks) == 0 && dial == nil && a.queryComment == "" {
+		return sql.Open(a.driverName, uri)
+	}
+
+	var (
+		c   driver.Connector
+		err error
+	)
+
+	if dial != nil {
+		c, err = openDialConnector(a.driverName, uri, dial)
+	} else {
+		c, err = openConnector(a.driverName, uri)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The comment is added before calling the hooks so it is also added to their queries
+	if a.queryComment != "" {
+		c = commentConnector{c, formatQueryComment(a.queryComment)}
+	}
+
+	if len(a.connHooks) > 0 {
+		c = hookConnector{c, a.connHooks}
+	}
+
+	return sql.OpenDB(c), nil
+}
+
 // Adapter implements a data backend adapter for PostgreSQL.
 type Adapter struct {
+	connOptions
+	schemaOptions
+	saveOptions
+	attrOptions
+	hookOptions
+
+	name           string
+	isolationLevel sql.IsolationLevel
+	retryPolicy    RetryPolicy
+	clock          func() time.Time
+	db, readDB     *sql.DB
+	buffer         *txBuffer
+	writes         *writeBuffer
+	status         *status
+	serverVersion  *serverVersion
+	poolWatcher    *poolWatcher
+	analyzer       *analyzer
+	derived        bool
+}
+
+// connOptions contains the settings of the database connections.
+type connOptions struct {
 	host, user, password, database string
+	driverName                     string
 	port                           uint
+	hosts                          []HostPort
+	targetSessionAttrs             string
+	keepAlive                      time.Duration
 	params                         map[string]string
-	db                             *sql.DB
-	schemas                        Schemas
+	searchPath                     []string
+	role                           string
+	queryComment                   string
+	connMaxIdleTime                time.Duration
+	maxOpenConns, maxIdleConns     int
+	separatePools, createDatabase  bool
+	connHooks                      []ConnectionHook
+	dialer                         DialFunc
+	poolWatcherFn                  func(sql.DBStats)
+	poolWatcherInterval            time.Duration
+}
+
+// schemaOptions contains the settings used to update the database schema.
+type schemaOptions struct {
+	schemas                     Schemas
+	requireSchema, schemaNotify bool
+	valueColumnType             string
+	migrationLockTimeout        time.Duration
+	lockTimeout                 time.Duration
+}
+
+// saveOptions contains the settings used to save transactions.
+type saveOptions struct {
+	chainID                       string
+	notify, typeHints, epochTime  bool
+	strictValidation              bool
+	continueOnError, txSavepoints bool
+	txConflictPolicy              ConflictPolicy
+	merge, rawCompression         bool
+	batchSize, maxBatchBytes      int
+	flushInterval                 time.Duration
+	writeBufferCap                int
+	blockTimeResolver             BlockTimeResolver
+	requireBlockTime              bool
+	autoAnalyzeThreshold          int
+}
+
+// attrOptions contains the settings used to save event attributes.
+type attrOptions struct {
+	attrEncoder        AttributeEncoder
+	attrAllowlist      map[string]struct{}
+	eventAllowlist     map[string]struct{}
+	attrConflictPolicy ConflictPolicy
+	skipBadAttrs       bool
+}
+
+// hookOptions contains the functions that are called while saving transactions.
+type hookOptions struct {
+	preCommit      func(context.Context, []cosmosclient.TX) error
+	postCommit     func(context.Context, []cosmosclient.TX)
+	batchCommitted func(int64) error
+	attrsSkipped   func(context.Context, []SkippedAttribute)
+}
+
+// With returns a copy of the adapter with extra options applied to it.
+// The new adapter shares the database connection pool with the original one, so
+// options that configure the database connection have no effect on it, and it
+// can't be closed because closing it would close the pool of the original adapter.
+// The new adapter doesn't use the write buffer of the original adapter, which saves
+// the transactions using the options of the original adapter, so Save saves the
+// transactions without buffering them. It also has its own buffer for the transactions
+// received by SaveStream, so Flush only saves the transactions of its own streams.
+// An OptionErrors error is returned when the options are not valid.
+func (a Adapter) With(options ...Option) (Adapter, error) {
+	for _, o := range options {
+		o(&a)
+	}
+
+	if err := a.validateOptions(); err != nil {
+		return Adapter{}, err
+	}
+
+	a.derived = true
+	a.writes = nil
+	a.buffer = &txBuffer{}
+
+	return a, nil
+}
+
+// Warmup opens database connections in advance so they are ready to be used.
+// It opens up to "n" connections concurrently and checks that each one of them
+// is alive. The number of connections is limited by the maximum number of open
+// connections, and only up to the maximum number of idle connections are kept
+// open in the pool after the warmup.
+func (a Adapter) Warmup(ctx context.Context, n int) error {
+	db, err := a.getDB()
+	if err != nil {
+		return err
+	}
+
+	if limit := db.Stats().MaxOpenConnections; limit > 0 && n > limit {
+		n = limit
+	}
+
+	// All the connections must be open at the same time, otherwise the
+	// pool would reuse the same connection for all the checks
+	conns := make([]*sql.Conn, n)
+
+	defer func() {
+		for _, c := range conns {
+			if c != nil {
+				c.Close()
+			}
+		}
+	}()
+
+	g, ctx := errgroup.WithContext(ctx)
+	for i := range conns {
+		i := i
+
+		g.Go(func() error {
+			c, err := db.Conn(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to open connection: %w", err)
+			}
+
+			conns[i] = c
+
+			return c.PingContext(ctx)
+		})
+	}
+
+	return g.Wait()
 }
 
 // UpdateSchema updates the database schema to the latest version available.
 // It applies all available schemas that were not applied already.
+// The schema is locked during the update so only one process at a time can update it.
 func (a Adapter) UpdateSchema(ctx context.Context, s Schemas) error {
+	_, err := a.UpdateSchemaChanged(ctx, s)
+	return err
+}
+
+// UpdateSchemaChanged updates the database schema to the latest version available.
+// It returns true when at least one schema was applied, or false when the
+// database schema was already updated to the latest version.
+func (a Adapter) UpdateSchemaChanged(ctx context.Context, s Schemas) (changed bool, err error) {
+	if err := s.Validate(); err != nil {
+		return false, err
+	}
+
 	db, err := a.getDB()
 	if err != nil {
-		return err
+		return false, err
+	}
+
+	// The same connection must be used during the update because the lock
+	// is held by the database session
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer conn.Close()
+
+	unlock, err := a.lockSchema(ctx, conn, s)
+	if err != nil {
+		return false, err
+	}
+
+	defer unlock()
+
+	resetLockTimeout, err := a.setLockTimeout(ctx, conn)
+	if err != nil {
+		return false, err
+	}
+
+	defer resetLockTimeout()
+
+	if a.requireSchema {
+		exists, err := schemaTableExists(ctx, conn, s)
+		if err != nil {
+			return false, err
+		}
+
+		if !exists {
+			return false, ErrSchemaNotFound
+		}
 	}
 
 	// Create the schema table if it doesn't exists
-	if _, err := db.ExecContext(ctx, s.GetTableDDL()); err != nil {
-		return fmt.Errorf("failed to check schema table: %w", err)
+	if _, err := conn.ExecContext(ctx, s.GetTableDDL()); err != nil {
+		return false, fmt.Errorf("failed to check schema table: %w", err)
 	}
 
 	// Get the current schema version
 	var v uint64
-	if err := db.QueryRowContext(ctx, s.GetSchemaVersionSQL()).Scan(&v); err != nil {
-		return fmt.Errorf("failed to read current schema version: %w", err)
+	if err := conn.QueryRowContext(ctx, s.GetSchemaVersionSQL()).Scan(&v); err != nil {
+		return false, fmt.Errorf("failed to read current schema version: %w", err)
 	}
 
-	return s.WalkFrom(v+1, func(version uint64, script []byte) error {
-		if _, err := db.ExecContext(ctx, string(script)); err != nil {
-			return fmt.Errorf("error applying schema version %d: %w", version, err)
+	err = s.WalkFrom(v+1, func(version uint64, script []byte) error {
+		// The first adapter schema creates the attribute table
+		if version == 1 && s.tableName == a.schemas.tableName {
+			script = a.withValueColumnType(script)
 		}
 
+		if _, err := conn.ExecContext(ctx, string(script)); err != nil {
+			return a.newSchemaError(uint(version), script, a.wrapLockTimeout(err))
+		}
+
+		changed = true
+		v = version
+
 		return nil
 	})
-}
+	if err != nil {
+		return changed, err
+	}
 
-func (a Adapter) GetType() string {
-	return adapterType
-}
+	// The notification is only sent when the schema changed
+	if changed && a.schemaNotify {
+		if err := notifySchema(ctx, conn, v); err != nil {
+			return changed, err
+		}
+	}
 
-func (a Adapter) Init(ctx context.Context) error {
-	return a.UpdateSchema(ctx, a.schemas)
+	return changed, nil
 }
 
-func (a Adapter) Save(ctx context.Context, txs []cosmosclient.TX) error {
+// ValidateMigrations checks that the schemas that are not yet applied to the database are valid.
+// The schemas are applied within a database transaction that is always rolled back, so errors in
+// the SQL scripts are found using the current database schema without changing the database.
+// A SchemaError is returned for the first schema that can't be applied.
+// Schema scripts with statements that can't run within a database transaction can't be validated.
+func (a Adapter) ValidateMigrations(ctx context.Context) error {
+	files, err := a.schemaFiles()
+	if err != nil {
+		return err
+	}
+
 	db, err := a.getDB()
 	if err != nil {
 		return err
 	}
 
-	// Start a transaction
-	sqlTx, err := db.BeginTx(ctx, nil)
+	// The schema is locked to avoid validating schemas while they are applied
+	conn, err := db.Conn(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Rollback won't have any effect if the transaction is committed before
-	defer sqlTx.Rollback()
+	defer conn.Close()
 
-	// Prepare insert statements to speed up "bulk" saving times
-	txStmt, err := sqlTx.PrepareContext(ctx, sqlInsertTX)
+	unlock, err := a.lockSchema(ctx, conn, a.schemas)
 	if err != nil {
 		return err
 	}
 
-	defer txStmt.Close()
+	defer unlock()
 
-	evtStmt, err := sqlTx.PrepareContext(ctx, sqlInsertEvent)
+	resetLockTimeout, err := a.setLockTimeout(ctx, conn)
 	if err != nil {
 		return err
 	}
 
-	defer evtStmt.Close()
+	defer resetLockTimeout()
+
+	v, err := getSchemaVersion(ctx, conn, a.schemas)
+	if err != nil {
+		return err
+	}
 
-	attrStmt, err := sqlTx.PrepareContext(ctx, sqlInsertEventAttr)
+	sqlTx, err := conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
-	defer attrStmt.Close()
+	// The transaction is never committed so the schemas are not applied
+	defer sqlTx.Rollback()
 
-	// All the transactions are saved within the context of the same database
-	// transactions and because of that either all block transactions are
-	// saved or none of them.
-	for _, tx := range txs {
-		if err := saveRawTX(ctx, sqlTx, tx.Raw); err != nil {
-			return err
+	for _, f := range files {
+		if f.Version <= v {
+			continue
 		}
 
-		if err := saveTX(ctx, txStmt, evtStmt, attrStmt, tx); err != nil {
-			return err
+		if _, err := sqlTx.ExecContext(ctx, string(f.Content)); err != nil {
+			return a.newSchemaError(f.Version, f.Content, a.wrapLockTimeout(err))
 		}
 	}
 
-	return sqlTx.Commit()
+	return nil
 }
 
-func (a Adapter) GetLatestHeight(ctx context.Context) (height int64, err error) {
+// SchemaVersion returns the version of the schema applied to the database.
+// Zero is returned when no schema has been applied to the database.
+func (a Adapter) SchemaVersion(ctx context.Context) (version uint, err error) {
 	db, err := a.getDB()
 	if err != nil {
 		return 0, err
 	}
 
-	row := db.QueryRowContext(ctx, sqlSelectBlockHeight)
-	if err = row.Scan(&height); err != nil {
+	err = a.withRetry(ctx, func() (err error) {
+		version, err = getSchemaVersion(ctx, db, a.schemas)
+		return err
+	})
+	if err != nil {
 		return 0, err
 	}
 
-	return height, nil
+	return version, nil
 }
 
-func (a Adapter) QueryEvents(ctx context.Context, q query.EventQuery) ([]query.Event, error) {
-	db, err := a.getDB()
-	if err != nil {
-		return nil, err
+// Close saves the transactions waiting to be saved and closes the database.
+// Adapters that share the database connection pool can't be used after closing it.
+// ErrDerivedAdapter is returned for adapters created with With.
+func (a Adapter) Close() error {
+	if a.derived {
+		return ErrDerivedAdapter
 	}
 
-	sql := parseEventQuery(q)
-	args := extractEventQueryArgs(q)
-	rows, err := db.QueryContext(ctx, sql, args...)
+	db, err := a.getDB()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var (
-		events   []query.Event
-		eventIDs []int64
-
-		// Keep an index of the event position within the events slice
-		// to find them later when updating their attributes.
-		eventIndexes = make(map[int64]int)
-	)
-
-	for i := 0; rows.Next(); i++ {
-		e := query.Event{}
-		if err := rows.Scan(&e.ID, &e.Index, &e.TXHash, &e.Type, &e.CreatedAt); err != nil {
-			return nil, fmt.Errorf("failed to read event: %w", err)
-		}
-
-		events = append(events, e)
-		eventIDs = append(eventIDs, e.ID)
+	// The buffered writes must be saved before flushing the stream transactions
+	var werr error
+	if a.writes != nil {
+		werr = a.writes.close()
+	}
 
-		eventIndexes[e.ID] = i
+	ferr := a.Flush(context.Background())
+	if ferr == nil {
+		ferr = werr
 	}
 
-	// Don't query attributes when there are no events
-	if len(events) == 0 {
-		return events, nil
+	if a.poolWatcher != nil {
+		a.poolWatcher.close()
 	}
 
-	// Select the attributes for the events that matched the query
-	rows, err = db.QueryContext(ctx, sqlSelectEventAttrs, pq.Array(eventIDs))
-	if err != nil {
-		return nil, err
+	if a.analyzer != nil {
+		a.analyzer.close()
 	}
 
-	// Update the attributes of the selected events
-	for rows.Next() {
-		var (
-			eventID int64
-			name    string
-			value   []byte
-		)
+	var rerr error
+	if a.readDB != nil {
+		rerr = a.readDB.Close()
+	}
 
-		if err := rows.Scan(&eventID, &name, &value); err != nil {
-			return nil, fmt.Errorf("failed to read event attribute: %w", err)
-		}
+	// The database is closed even when the pending transactions can't be saved
+	if err := db.Close(); err != nil {
+		return err
+	}
 
-		i := eventIndexes[eventID]
-		events[i].Attributes = append(events[i].Attributes, query.NewAttribute(name, value))
+	if rerr != nil {
+		return rerr
 	}
 
-	return events, nil
+	return ferr
 }
 
-func (a Adapter) Query(ctx context.Context, q query.Query) (query.Cursor, error) {
-	db, err := a.getDB()
-	if err != nil {
-		return nil, err
-	}
+// DB returns the database connection pool used by the adapter.
+// It allows running custom queries or migrations using the same connections.
+// The pool is owned by the adapter so callers must not close it, the adapter
+// must be closed instead.
+func (a Adapter) DB() (*sql.DB, error) {
+	return a.getDB()
+}
 
-	sql, err := parseQuery(q)
-	if err != nil {
-		return nil, err
-	}
+func (a Adapter) GetType() string {
+	return adapterType
+}
 
-	args := extractQueryArgs(q)
-	rows, err := db.QueryContext(ctx, sql, args...)
-	if err != nil {
-		return nil, err
+// Name returns the name of the adapter.
+// The name of the database is returned when the adapter doesn't have a name.
+func (a Adapter) Name() string {
+	if a.name == "" {
+		return a.database
 	}
 
-	return rows, nil
+	return a.name
+}
+
+func (a Adapter) Init(ctx context.Context) error {
+	return a.UpdateSchema(ctx, a.schemas)
 }
 
 func (a Adapter) getDB() (*sql.DB, error) {
@@ -322,11 +636,23 @@ func (a Adapter) getDB() (*sql.DB, error) {
 	return a.db, nil
 }
 
+// getReadDB returns the database connection pool for read queries.
+// The pool for writes is returned when there is no separate pool for reads.
+func (a Adapter) getReadDB() (*sql.DB, error) {
+	if a.readDB != nil {
+		return a.readDB, nil
+	}
+
+	return a.getDB()
+}
+
 func createPostgresURI(a Adapter) string {
+	// The raw path is used to also escape the slashes in the database name
 	uri := url.URL{
-		Scheme: adapterType,
-		Host:   fmt.Sprintf("%s:%d", a.host, a.port),
-		Path:   a.database,
+		Scheme:  adapterType,
+		Host:    formatHosts(a),
+		Path:    a.database,
+		RawPath: url.PathEscape(a.database),
 	}
 
 	if a.user != "" {
@@ -337,87 +663,98 @@ func createPostgresURI(a Adapter) string {
 		}
 	}
 
-	// Add extra params as query arguments
-	if a.params != nil {
-		query := url.Values{}
-		for k, v := range a.params {
-			query.Set(k, v)
-		}
-
+	if query := connParams(a); len(query) > 0 {
 		uri.RawQuery = query.Encode()
 	}
 
 	return uri.String()
 }
 
-func saveRawTX(ctx context.Context, sqlTx *sql.Tx, rtx *ctypes.ResultTx) error {
-	hash := rtx.Hash.String()
-	raw, err := json.Marshal(rtx)
-	if err != nil {
-		return fmt.Errorf("failed to encode raw TX %s: %w", hash, err)
+// connParams returns the parameters of the database connection.
+// They include the extra parameters and the ones configured with specific options.
+func connParams(a Adapter) url.Values {
+	query := url.Values{}
+	for k, v := range a.params {
+		query.Set(k, v)
 	}
 
-	if _, err := sqlTx.ExecContext(ctx, sqlInsertRawTX, hash, raw); err != nil {
-		return fmt.Errorf("error saving raw TX %s: %w", hash, err)
+	// The search path and role are set for each connection using the
+	// command-line options that are sent to the server when the connection starts
+	if len(a.searchPath) > 0 {
+		opts := strings.TrimSpace(fmt.Sprintf("%s -c search_path=%s", query.Get(paramOptions), formatSearchPath(a.searchPath)))
+		query.Set(paramOptions, opts)
 	}
 
-	return nil
-}
-
-func saveTX(ctx context.Context, txStmt, evtStmt, attrStmt *sql.Stmt, tx cosmosclient.TX) error {
-	hash := tx.Raw.Hash.String()
-	if _, err := txStmt.ExecContext(ctx, hash, tx.Raw.Index, tx.Raw.Height, tx.BlockTime); err != nil {
-		return fmt.Errorf("error saving TX %s: %w", hash, err)
+	if a.role != "" {
+		opts := strings.TrimSpace(fmt.Sprintf("%s -c role=%s", query.Get(paramOptions), escapeOption(a.role)))
+		query.Set(paramOptions, opts)
 	}
 
-	events, err := tx.GetEvents()
-	if err != nil {
-		return err
+	// Add the connection parameters that are configured with specific options
+	for k, v := range map[string]string{
+		paramTargetSessionAttrs: a.targetSessionAttrs,
+	} {
+		if v != "" {
+			query.Set(k, v)
+		}
 	}
 
-	for i, evt := range events {
-		var evtID int
+	return query
+}
 
-		row := evtStmt.QueryRowContext(ctx, hash, evt.Type, i)
-		if err := row.Err(); err != nil {
-			return fmt.Errorf("error saving event '%s': %w", evt.Type, err)
-		}
+// formatHosts returns the database hosts as a comma separated list of host and port pairs.
+func formatHosts(a Adapter) string {
+	if len(a.hosts) == 0 {
+		return fmt.Sprintf("%s:%d", a.host, a.port)
+	}
 
-		if err := row.Scan(&evtID); err != nil {
-			return fmt.Errorf("error reading event ID: %w", err)
+	hosts := make([]string, len(a.hosts))
+	for i, h := range a.hosts {
+		port := h.Port
+		if port == 0 {
+			port = DefaultPort
 		}
 
-		for _, attr := range evt.Attributes {
-			if _, err := attrStmt.ExecContext(ctx, evtID, attr.Key, attr.Value); err != nil {
-				return fmt.Errorf("error saving event attr '%s.%s': %w", evt.Type, attr.Key, err)
-			}
-		}
+		hosts[i] = fmt.Sprintf("%s:%d", h.Host, port)
 	}
 
-	return nil
+	return strings.Join(hosts, ",")
 }
 
-func extractQueryArgs(q query.Query) []any {
-	// When the query is a call to a postgres function
-	// add the arguments before the filter values
-	args := q.Args()
-
-	// Add the values from the filters
-	for _, f := range q.Filters() {
-		if a := f.Value(); a != nil {
-			args = append(args, a)
-		}
+func formatSearchPath(schemas []string) string {
+	names := make([]string, len(schemas))
+	for i, s := range schemas {
+		names[i] = escapeOption(pq.QuoteIdentifier(s))
 	}
 
-	return args
+	return strings.Join(names, ",")
 }
 
-func extractEventQueryArgs(q query.EventQuery) (args []any) {
-	for _, f := range q.Filters() {
-		if a := f.Value(); a != nil {
-			args = append(args, a)
-		}
+// escapeOption escapes a value of the connection command-line options.
+// Spaces and backslashes must be escaped in the connection options.
+func escapeOption(v string) string {
+	return strings.NewReplacer(`\`, `\\`, " ", `\ `).Replace(v)
+}
+
+// rowQuerier defines an interface for types that can query a single row.
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// querier defines an interface for types that can run queries.
+// It is implemented by database connection pools and transactions.
+type querier interface {
+	rowQuerier
+
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// now returns the current time in UTC.
+// The time is saved in UTC because the time columns don't have a time zone.
+func (a Adapter) now() time.Time {
+	if a.clock == nil {
+		return time.Now().UTC()
 	}
 
-	return args
+	return a.clock().UTC()
 }
@@ -0,0 +1,240 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrReadOnlyQuery is returned when an ad-hoc query is not a read-only query.
+	ErrReadOnlyQuery = errors.New("query must be a SELECT or WITH query")
+
+	// ErrMultipleStatements is returned when an ad-hoc query contains more than one statement.
+	ErrMultipleStatements = errors.New("query must be a single statement")
+)
+
+// QueryMaps executes a read-only query and returns the rows as maps indexed by column name.
+// It allows running ad-hoc queries, for example from admin tools, without knowing the
+// columns of the results in advance. Only SELECT and WITH queries are allowed, and they
+// are executed within a read-only database transaction. Queries with more than one statement
+// are not allowed because the extra statements could end the read-only transaction. JSON
+// column values are decoded into nested values, and text values are returned as strings.
+func (a Adapter) QueryMaps(ctx context.Context, query string, args ...any) (results []map[string]any, err error) {
+	if err := validateReadOnlyQuery(query); err != nil {
+		return nil, err
+	}
+
+	defer func() { a.recordStatus(err) }()
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	// The transaction makes sure that the query doesn't change the database,
+	// even when it uses data modifying statements within a WITH query
+	sqlTx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: a.isolationLevel, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	defer sqlTx.Rollback()
+
+	// Prepared statements use the extended query protocol, where the server rejects
+	// queries with more than one statement, even when the query doesn't have arguments
+	stmt, err := sqlTx.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		values := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]any, len(columns))
+		for i, c := range columns {
+			if row[c.Name()], err = decodeColumnValue(c, values[i]); err != nil {
+				return nil, fmt.Errorf("error decoding column '%s': %w", c.Name(), err)
+			}
+		}
+
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// validateReadOnlyQuery checks that a query is a single statement that starts with a SELECT or WITH keyword.
+func validateReadOnlyQuery(query string) error {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ErrReadOnlyQuery
+	}
+
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "WITH":
+	default:
+		return ErrReadOnlyQuery
+	}
+
+	if hasMultipleStatements(query) {
+		return ErrMultipleStatements
+	}
+
+	return nil
+}
+
+// hasMultipleStatements checks if a query has statement separators outside of the string
+// literals, quoted identifiers and comments. A separator at the end of the query is allowed.
+func hasMultipleStatements(query string) bool {
+	end := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		switch {
+		case c == '\'' || c == '"':
+			// Escape strings allow escaping the quotes with a backslash
+			escapes := c == '\'' && i > 0 && (query[i-1] == 'E' || query[i-1] == 'e') && (i == 1 || !isIdentChar(query[i-2]))
+			i = skipQuoted(query, i, c, escapes)
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			if n := strings.IndexByte(query[i:], '\n'); n != -1 {
+				i += n
+			} else {
+				i = len(query)
+			}
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			i = skipBlockComment(query, i)
+		case c == '$' && (i == 0 || !isIdentChar(query[i-1])):
+			i = skipDollarQuoted(query, i)
+		case c == ';':
+			end = true
+		case end && !unicode.IsSpace(rune(c)):
+			return true
+		}
+	}
+
+	return false
+}
+
+// skipQuoted returns the position of the quote that ends a quoted string or identifier.
+// Quotes are escaped by doubling them, or with a backslash in escape strings.
+func skipQuoted(query string, start int, quote byte, escapes bool) int {
+	for i := start + 1; i < len(query); i++ {
+		switch {
+		case escapes && query[i] == '\\':
+			i++
+		case query[i] == quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+				continue
+			}
+
+			return i
+		}
+	}
+
+	return len(query)
+}
+
+// skipBlockComment returns the position of the end of a block comment.
+// Block comments can be nested.
+func skipBlockComment(query string, start int) int {
+	depth := 0
+	for i := start; i < len(query)-1; i++ {
+		switch query[i : i+2] {
+		case "/*":
+			depth++
+			i++
+		case "*/":
+			depth--
+			i++
+
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return len(query)
+}
+
+// skipDollarQuoted returns the position of the end of a dollar quoted string.
+// The position doesn't change when the dollar sign doesn't start a dollar quoted string,
+// for example when it is a query parameter.
+func skipDollarQuoted(query string, start int) int {
+	end := strings.IndexByte(query[start+1:], '$')
+	if end == -1 {
+		return start
+	}
+
+	tag := query[start : start+end+2]
+	for i, c := range []byte(tag[1 : len(tag)-1]) {
+		if !isIdentChar(c) || (i == 0 && c >= '0' && c <= '9') {
+			return start
+		}
+	}
+
+	n := strings.Index(query[start+len(tag):], tag)
+	if n == -1 {
+		return len(query)
+	}
+
+	return start + len(tag) + n + len(tag) - 1
+}
+
+// isIdentChar checks if a character can be part of an unquoted identifier.
+func isIdentChar(c byte) bool {
+	return c == '_' || c >= 0x80 || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// decodeColumnValue decodes a value read from a column of a query result.
+func decodeColumnValue(c *sql.ColumnType, v any) (any, error) {
+	data, ok := v.([]byte)
+	if !ok {
+		return v, nil
+	}
+
+	switch c.DatabaseTypeName() {
+	case "JSON", "JSONB":
+		var value any
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+
+		return value, nil
+	case "BYTEA":
+		return data, nil
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryMaps(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+	query := "SELECT hash, height, data FROM tx WHERE height = $1"
+	want := []map[string]any{
+		{
+			"hash":   "F2564C78",
+			"height": int64(1),
+			"data":   map[string]any{"code": float64(0)},
+		},
+	}
+
+	// Arrange: Database mock and expectations
+	rows := sqlmock.NewRowsWithColumnDefinition(
+		sqlmock.NewColumn("hash").OfType("VARCHAR", ""),
+		sqlmock.NewColumn("height").OfType("INT8", int64(0)),
+		sqlmock.NewColumn("data").OfType("JSONB", []byte{}),
+	)
+	rows.AddRow([]byte("F2564C78"), int64(1), []byte(`{"code":0}`))
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare(query).ExpectQuery().WithArgs(1).WillReturnRows(rows)
+	mock.ExpectRollback()
+
+	// Act
+	results, err := adapter.QueryMaps(context.Background(), query, 1)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, want, results)
+}
+
+func TestQueryMapsMultipleStatements(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+
+	// Act: The query is rejected before a database transaction is started
+	_, err := adapter.QueryMaps(context.Background(), "SELECT 1; COMMIT; DROP TABLE tx")
+
+	// Assert
+	require.ErrorIs(t, err, ErrMultipleStatements)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestValidateReadOnlyQuery(t *testing.T) {
+	cases := []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{
+			name:  "select",
+			query: "SELECT 1",
+		},
+		{
+			name:  "with",
+			query: "\n\twith t AS (SELECT 1) SELECT * FROM t",
+		},
+		{
+			name:  "delete",
+			query: "DELETE FROM tx",
+			err:   ErrReadOnlyQuery,
+		},
+		{
+			name: "empty",
+			err:  ErrReadOnlyQuery,
+		},
+		{
+			name:  "trailing separator",
+			query: "SELECT 1; -- comment\n",
+		},
+		{
+			name:  "separators in literals and comments",
+			query: `SELECT ';', ";", E'\';', $$;$$, $tag$;$tag$, $1 /* ; /* ; */ ; */ -- ;`,
+		},
+		{
+			name:  "multiple statements",
+			query: "SELECT 1; COMMIT; DROP TABLE tx",
+			err:   ErrMultipleStatements,
+		},
+		{
+			name:  "multiple statements after literal",
+			query: "SELECT 'a''b'; DROP TABLE tx",
+			err:   ErrMultipleStatements,
+		},
+		{
+			name:  "multiple statements after backslash",
+			query: `SELECT 'a\'; DROP TABLE tx; --'`,
+			err:   ErrMultipleStatements,
+		},
+		{
+			name:  "multiple statements after parameter",
+			query: "SELECT $1; DROP TABLE tx",
+			err:   ErrMultipleStatements,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, validateReadOnlyQuery(tt.query), tt.err)
+		})
+	}
+}
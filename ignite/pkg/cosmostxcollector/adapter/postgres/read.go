@@ -0,0 +1,669 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+	"github.com/ignite/cli/ignite/pkg/cosmostxcollector/query"
+)
+
+const (
+	sqlSelectBlockHeight = `
+		SELECT COALESCE(MAX(height), 0)
+		FROM tx
+	`
+	sqlCountTXsByHeightRange = `
+		SELECT COUNT(*)
+		FROM tx
+		WHERE tx.height BETWEEN $1 AND $2
+	`
+	sqlSelectTXExists = `
+		SELECT EXISTS(SELECT 1 FROM tx WHERE hash = $1)
+	`
+	sqlSelectTXHashesByHeightRange = `
+		SELECT hash FROM tx
+		WHERE height BETWEEN $1 AND $2
+		ORDER BY height, index
+	`
+	sqlSelectTXLog = `
+		SELECT raw_log FROM tx WHERE hash = $1
+	`
+	sqlSelectTXMeta = `
+		SELECT
+			hash, height, index,
+			COALESCE(block_time, to_timestamp(block_time_ms / 1000.0) AT TIME ZONE 'UTC'),
+			code, COALESCE(codespace, ''), COALESCE(raw_log, '')
+		FROM tx
+		WHERE hash = $1
+	`
+	sqlSelectChainHeights = `
+		SELECT COALESCE(chain_id, ''), MAX(height)
+		FROM tx
+		GROUP BY chain_id
+	`
+	sqlSelectEventAttrs = `
+		SELECT event_id, name, value, value_type FROM attribute
+		WHERE event_id = ANY($1)
+		ORDER BY event_id
+	`
+	tplSelectTXsSQL = `
+		SELECT
+			COALESCE(tx.block_time, to_timestamp(tx.block_time_ms / 1000.0) AT TIME ZONE 'UTC'),
+			COALESCE(convert_to(raw_tx.data, 'UTF8'), raw_tx.data_gz)
+		FROM tx INNER JOIN raw_tx ON tx.hash = raw_tx.hash
+		%s
+	`
+	sqlTXsByHeightRangeClauses = `
+		WHERE tx.height BETWEEN $1 AND $2
+		ORDER BY tx.height, tx.index
+	`
+	sqlTXsByTimeRangeClauses = `
+		WHERE tx.block_time BETWEEN $1 AND $2
+		ORDER BY tx.block_time, tx.height, tx.index
+		LIMIT $3
+	`
+	sqlTXsByEpochTimeRangeClauses = `
+		WHERE tx.block_time_ms BETWEEN $1 AND $2
+		ORDER BY tx.block_time_ms, tx.height, tx.index
+		LIMIT $3
+	`
+	sqlTXAtHeightIndexClauses = `
+		WHERE tx.height = $1 AND tx.index = $2
+	`
+	sqlTXByHashClauses = `
+		WHERE tx.hash = $1
+	`
+	sqlFailedTXsByHeightRangeClauses = `
+		WHERE tx.height BETWEEN $1 AND $2 AND tx.code <> 0
+		ORDER BY tx.height, tx.index
+	`
+	sqlRecentTXsClauses = `
+		ORDER BY tx.height DESC, tx.index DESC
+		LIMIT $1
+	`
+	sqlTXsByHeightsClauses = `
+		WHERE tx.height = ANY($1)
+		ORDER BY tx.height, tx.index
+	`
+)
+
+func (a Adapter) GetLatestHeight(ctx context.Context) (height int64, err error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return 0, err
+	}
+
+	err = a.withRetry(ctx, func() error {
+		return db.QueryRowContext(ctx, sqlSelectBlockHeight).Scan(&height)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return height, nil
+}
+
+// GetLatestHeightsByChain returns the latest block height of each chain.
+// The transactions saved without a chain ID are returned with an empty chain ID.
+// An empty map is returned when there are no saved transactions.
+func (a Adapter) GetLatestHeightsByChain(ctx context.Context) (heights map[string]int64, err error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.withRetry(ctx, func() (err error) {
+		heights, err = fetchChainHeights(ctx, db)
+		return err
+	})
+
+	return heights, err
+}
+
+// fetchChainHeights selects the latest block height of each chain.
+func fetchChainHeights(ctx context.Context, db querier) (map[string]int64, error) {
+	rows, err := db.QueryContext(ctx, sqlSelectChainHeights)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	heights := map[string]int64{}
+	for rows.Next() {
+		var (
+			chainID string
+			height  int64
+		)
+
+		if err := rows.Scan(&chainID, &height); err != nil {
+			return nil, err
+		}
+
+		heights[chainID] = height
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return heights, nil
+}
+
+// GetTXAtHeightIndex returns the transaction with a specific index within a block.
+// ErrNotFound is returned when the block doesn't have a transaction with the index.
+func (a Adapter) GetTXAtHeightIndex(ctx context.Context, height int64, index uint32) (cosmosclient.TX, error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return cosmosclient.TX{}, err
+	}
+
+	txs, err := a.queryTXs(ctx, db, sqlTXAtHeightIndexClauses, height, index)
+	if err != nil {
+		return cosmosclient.TX{}, err
+	}
+
+	if len(txs) == 0 {
+		return cosmosclient.TX{}, fmt.Errorf("%w: height %d index %d", ErrNotFound, height, index)
+	}
+
+	return txs[0], nil
+}
+
+// GetTX returns a transaction with all its data.
+// The transaction is decoded from its raw data, which includes the events and their attributes.
+// GetTXMeta can be used instead when only the values of the transaction are needed.
+// ErrNotFound is returned when the transaction doesn't exist.
+func (a Adapter) GetTX(ctx context.Context, hash string) (cosmosclient.TX, error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return cosmosclient.TX{}, err
+	}
+
+	txs, err := a.queryTXs(ctx, db, sqlTXByHashClauses, hash)
+	if err != nil {
+		return cosmosclient.TX{}, err
+	}
+
+	if len(txs) == 0 {
+		return cosmosclient.TX{}, fmt.Errorf("%w: %s", ErrNotFound, hash)
+	}
+
+	return txs[0], nil
+}
+
+// GetTXMeta returns the values of a transaction without reading its raw data.
+// It is cheaper than GetTX for lookups that don't need the events because only the
+// transactions table is read. The transaction contains the hash, block height and time,
+// index, result code, codespace and raw log, while its events and raw data are empty.
+// ErrNotFound is returned when the transaction doesn't exist.
+func (a Adapter) GetTXMeta(ctx context.Context, hash string) (tx cosmosclient.TX, err error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return cosmosclient.TX{}, err
+	}
+
+	var (
+		txHash    string
+		blockTime sql.NullTime
+		raw       ctypes.ResultTx
+	)
+
+	err = a.withRetry(ctx, func() error {
+		row := db.QueryRowContext(ctx, sqlSelectTXMeta, hash)
+		return row.Scan(&txHash, &raw.Height, &raw.Index, &blockTime, &raw.TxResult.Code, &raw.TxResult.Codespace, &raw.TxResult.Log)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return cosmosclient.TX{}, fmt.Errorf("%w: %s", ErrNotFound, hash)
+		}
+
+		return cosmosclient.TX{}, fmt.Errorf("failed to read TX: %w", err)
+	}
+
+	if raw.Hash, err = hex.DecodeString(txHash); err != nil {
+		return cosmosclient.TX{}, fmt.Errorf("failed to decode TX hash: %w", err)
+	}
+
+	return cosmosclient.TX{BlockTime: blockTime.Time, Raw: &raw}, nil
+}
+
+// CountTXsInRange returns the number of transactions within a block height range.
+// It can be used to check that all the transactions were saved after saving them
+// in bulk. The range includes both the "from" and "to" block heights.
+func (a Adapter) CountTXsInRange(ctx context.Context, from, to int64) (count int64, err error) {
+	if err := validateHeightRange(from, to); err != nil {
+		return 0, err
+	}
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return 0, err
+	}
+
+	err = a.withRetry(ctx, func() error {
+		return db.QueryRowContext(ctx, sqlCountTXsByHeightRange, from, to).Scan(&count)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetTXHashesByHeightRange returns the hashes of the transactions within a block height range.
+// It is cheaper than reading the transactions when only the hashes are needed, for example to
+// compare the saved transactions with the ones of a node. Hashes are sorted by block height and
+// transaction index. The range includes both the "from" and "to" block heights.
+func (a Adapter) GetTXHashesByHeightRange(ctx context.Context, from, to int64) (hashes []string, err error) {
+	if err := validateHeightRange(from, to); err != nil {
+		return nil, err
+	}
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.withRetry(ctx, func() (err error) {
+		hashes, err = fetchTXHashes(ctx, db, from, to)
+		return err
+	})
+
+	return hashes, err
+}
+
+// fetchTXHashes selects the hashes of the transactions within a block height range without retrying.
+func fetchTXHashes(ctx context.Context, db querier, from, to int64) ([]string, error) {
+	rows, err := db.QueryContext(ctx, sqlSelectTXHashesByHeightRange, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+
+		hashes = append(hashes, hash)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+// Exists checks if a transaction is saved in the database.
+// It is cheaper than reading the transaction when only its existence matters.
+func (a Adapter) Exists(ctx context.Context, hash string) (exists bool, err error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return false, err
+	}
+
+	err = a.withRetry(ctx, func() error {
+		return db.QueryRowContext(ctx, sqlSelectTXExists, hash).Scan(&exists)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// GetTXLog returns the raw log of a transaction.
+// The log is saved without changes, as it is returned by the node.
+// An empty log is returned for the transactions saved without it.
+func (a Adapter) GetTXLog(ctx context.Context, hash string) (log string, err error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return "", err
+	}
+
+	var rawLog sql.NullString
+
+	err = a.withRetry(ctx, func() error {
+		return db.QueryRowContext(ctx, sqlSelectTXLog, hash).Scan(&rawLog)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, hash)
+		}
+
+		return "", err
+	}
+
+	return rawLog.String, nil
+}
+
+// GetFailedTXs returns the failed transactions within a block height range.
+// Failed transactions are the ones with a result code different than zero.
+// The range includes both the "from" and "to" block heights.
+func (a Adapter) GetFailedTXs(ctx context.Context, from, to int64) ([]cosmosclient.TX, error) {
+	if err := validateHeightRange(from, to); err != nil {
+		return nil, err
+	}
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	return a.queryTXs(ctx, db, sqlFailedTXsByHeightRangeClauses, from, to)
+}
+
+// GetRecentTXs returns the most recent transactions.
+// Transactions are sorted from newest to oldest and up to "n" transactions are returned.
+// The number of transactions is limited to MaxRecentTXs to avoid reading too many of them.
+func (a Adapter) GetRecentTXs(ctx context.Context, n int) ([]cosmosclient.TX, error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if n <= 0 {
+		return nil, nil
+	}
+
+	return a.queryTXs(ctx, db, sqlRecentTXsClauses, limitRecentTXs(n))
+}
+
+// GetTXsByHeights returns the transactions of a list of block heights.
+// The heights don't need to be contiguous, and transactions are sorted by
+// block height and index. No transactions are returned for an empty list.
+func (a Adapter) GetTXsByHeights(ctx context.Context, heights []int64) ([]cosmosclient.TX, error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(heights) == 0 {
+		return nil, nil
+	}
+
+	return a.queryTXs(ctx, db, sqlTXsByHeightsClauses, pq.Array(heights))
+}
+
+// GetTXsByTimeRange returns the transactions with a block time within a time range.
+// Transactions are sorted chronologically and the range includes both the start
+// and end times. All the transactions within the range are returned when the limit
+// is zero.
+func (a Adapter) GetTXsByTimeRange(ctx context.Context, start, end time.Time, limit int) ([]cosmosclient.TX, error) {
+	clauses, args, err := timeRangeQuery(start, end, limit, a.epochTime)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	return a.queryTXs(ctx, db, clauses, args...)
+}
+
+// timeRangeQuery returns the SQL clauses and arguments to select the transactions within a time range.
+func timeRangeQuery(start, end time.Time, limit int, epochTime bool) (string, []any, error) {
+	if start.After(end) {
+		return "", nil, fmt.Errorf("%w: start time %s is after end time %s", ErrInvalidTimeRange, start, end)
+	}
+
+	// A NULL limit selects all the transactions
+	var l sql.NullInt64
+	if limit > 0 {
+		l = sql.NullInt64{Int64: int64(limit), Valid: true}
+	}
+
+	if epochTime {
+		return sqlTXsByEpochTimeRangeClauses, []any{start.UnixMilli(), end.UnixMilli(), l}, nil
+	}
+
+	return sqlTXsByTimeRangeClauses, []any{start, end, l}, nil
+}
+
+func (a Adapter) QueryEvents(ctx context.Context, q query.EventQuery) (events []query.Event, err error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	sqlQuery := parseEventQuery(q, a.attrTableSQL())
+	args := extractEventQueryArgs(q)
+
+	err = a.withRetry(ctx, func() (err error) {
+		events, err = fetchEvents(ctx, db, sqlQuery, args...)
+		return err
+	})
+
+	return events, err
+}
+
+// fetchEvents selects the events that match a query and their attributes without retrying.
+func fetchEvents(ctx context.Context, db querier, sqlQuery string, args ...any) ([]query.Event, error) {
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var (
+		events   []query.Event
+		eventIDs []int64
+
+		// Keep an index of the event position within the events slice
+		// to find them later when updating their attributes.
+		eventIndexes = make(map[int64]int)
+	)
+
+	for i := 0; rows.Next(); i++ {
+		e := query.Event{}
+		if err := rows.Scan(&e.ID, &e.Index, &e.TXHash, &e.Type, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to read event: %w", err)
+		}
+
+		events = append(events, e)
+		eventIDs = append(eventIDs, e.ID)
+
+		eventIndexes[e.ID] = i
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	// Don't query attributes when there are no events
+	if len(events) == 0 {
+		return events, nil
+	}
+
+	// Select the attributes for the events that matched the query
+	attrRows, err := db.QueryContext(ctx, sqlSelectEventAttrs, pq.Array(eventIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	defer attrRows.Close()
+
+	// Update the attributes of the selected events
+	for attrRows.Next() {
+		var (
+			eventID   int64
+			name      string
+			value     []byte
+			valueType sql.NullString
+		)
+
+		if err := attrRows.Scan(&eventID, &name, &value, &valueType); err != nil {
+			return nil, fmt.Errorf("failed to read event attribute: %w", err)
+		}
+
+		attr := query.NewAttribute(name, value)
+		attr.ValueType = valueType.String
+
+		i := eventIndexes[eventID]
+		events[i].Attributes = append(events[i].Attributes, attr)
+	}
+
+	if err := attrRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event attributes: %w", err)
+	}
+
+	return events, nil
+}
+
+func (a Adapter) Query(ctx context.Context, q query.Query) (cursor query.Cursor, err error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	sqlQuery, err := parseQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows *sql.Rows
+
+	args := extractQueryArgs(q)
+	err = a.withRetry(ctx, func() (err error) {
+		rows, err = db.QueryContext(ctx, sqlQuery, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// selectTXs selects transactions by combining the transactions table with the raw transactions.
+// The SQL clauses are added after the FROM clause of the select and must contain the
+// filtering, sorting and limits for the transactions being selected.
+func selectTXs(ctx context.Context, db querier, clauses string, args ...any) (*sql.Rows, error) {
+	return db.QueryContext(ctx, fmt.Sprintf(tplSelectTXsSQL, clauses), args...)
+}
+
+// queryTXs selects a list of transactions.
+// The SQL clauses are added after the FROM clause of the select.
+// The query is retried after connection errors using the retry policy.
+func (a Adapter) queryTXs(ctx context.Context, db *sql.DB, clauses string, args ...any) (txs []cosmosclient.TX, err error) {
+	err = a.withRetry(ctx, func() (err error) {
+		txs, err = fetchTXs(ctx, db, clauses, args...)
+		return err
+	})
+
+	return txs, err
+}
+
+// fetchTXs selects a list of transactions without retrying.
+func fetchTXs(ctx context.Context, db querier, clauses string, args ...any) ([]cosmosclient.TX, error) {
+	rows, err := selectTXs(ctx, db, clauses, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var txs []cosmosclient.TX
+
+	for rows.Next() {
+		tx, err := scanTX(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		txs = append(txs, tx)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
+
+// scanTX reads a transaction selected using the transactions select SQL template.
+func scanTX(rows *sql.Rows) (cosmosclient.TX, error) {
+	var (
+		tx        cosmosclient.TX
+		blockTime sql.NullTime
+		raw       []byte
+	)
+
+	// The block time is NULL when it was not known when the transaction was saved
+	if err := rows.Scan(&blockTime, &raw); err != nil {
+		return cosmosclient.TX{}, fmt.Errorf("failed to read TX: %w", err)
+	}
+
+	tx.BlockTime = blockTime.Time
+
+	raw, err := decompressRawTX(raw)
+	if err != nil {
+		return cosmosclient.TX{}, fmt.Errorf("failed to decompress raw TX: %w", err)
+	}
+
+	tx.Raw = &ctypes.ResultTx{}
+	if err := json.Unmarshal(raw, tx.Raw); err != nil {
+		return cosmosclient.TX{}, fmt.Errorf("failed to decode raw TX: %w", err)
+	}
+
+	return tx, nil
+}
+
+// limitRecentTXs limits the number of recent transactions to read.
+func limitRecentTXs(n int) int {
+	if n > MaxRecentTXs {
+		return MaxRecentTXs
+	}
+
+	return n
+}
+
+func validateHeightRange(from, to int64) error {
+	if from < 0 || from > to {
+		return fmt.Errorf("%w: from %d to %d", ErrInvalidHeightRange, from, to)
+	}
+
+	return nil
+}
+
+func extractQueryArgs(q query.Query) []any {
+	// When the query is a call to a postgres function
+	// add the arguments before the filter values
+	args := q.Args()
+
+	// Add the values from the filters
+	for _, f := range q.Filters() {
+		if a := f.Value(); a != nil {
+			args = append(args, a)
+		}
+	}
+
+	return args
+}
+
+func extractEventQueryArgs(q query.EventQuery) (args []any) {
+	for _, f := range q.Filters() {
+		if a := f.Value(); a != nil {
+			args = append(args, a)
+		}
+	}
+
+	return args
+}
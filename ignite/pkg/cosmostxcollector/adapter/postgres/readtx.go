@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+// ReadTx is a read-only database transaction.
+// All the reads done within the transaction see the same snapshot of the
+// database, so the results of different reads are consistent with each other
+// even when transactions are saved while reading. Reads are not retried after
+// connection errors because the transaction can't continue after them.
+// The transaction must be closed when it is no longer used.
+type ReadTx struct {
+	tx        *sql.Tx
+	epochTime bool
+}
+
+// ReadTx starts a read-only database transaction.
+// The transaction uses the repeatable read isolation level, or the serializable
+// isolation level when it is configured for the adapter, which guarantees that
+// all the reads see the same snapshot of the database.
+func (a Adapter) ReadTx(ctx context.Context) (*ReadTx, error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	level := sql.LevelRepeatableRead
+	if a.isolationLevel == sql.LevelSerializable {
+		level = sql.LevelSerializable
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: level, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReadTx{tx: tx, epochTime: a.epochTime}, nil
+}
+
+// Close ends the read-only database transaction.
+func (t *ReadTx) Close() error {
+	return t.tx.Rollback()
+}
+
+// GetLatestHeight returns the block height of the latest saved transaction.
+func (t *ReadTx) GetLatestHeight(ctx context.Context) (height int64, err error) {
+	if err := t.tx.QueryRowContext(ctx, sqlSelectBlockHeight).Scan(&height); err != nil {
+		return 0, err
+	}
+
+	return height, nil
+}
+
+// GetLatestHeightsByChain returns the latest block height of each chain.
+// The transactions saved without a chain ID are returned with an empty chain ID.
+func (t *ReadTx) GetLatestHeightsByChain(ctx context.Context) (map[string]int64, error) {
+	return fetchChainHeights(ctx, t.tx)
+}
+
+// CountTXsInRange returns the number of transactions within a block height range.
+// The range includes both the "from" and "to" block heights.
+func (t *ReadTx) CountTXsInRange(ctx context.Context, from, to int64) (count int64, err error) {
+	if err := validateHeightRange(from, to); err != nil {
+		return 0, err
+	}
+
+	if err := t.tx.QueryRowContext(ctx, sqlCountTXsByHeightRange, from, to).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetTXHashesByHeightRange returns the hashes of the transactions within a block height range.
+// Hashes are sorted by block height and transaction index.
+// The range includes both the "from" and "to" block heights.
+func (t *ReadTx) GetTXHashesByHeightRange(ctx context.Context, from, to int64) ([]string, error) {
+	if err := validateHeightRange(from, to); err != nil {
+		return nil, err
+	}
+
+	return fetchTXHashes(ctx, t.tx, from, to)
+}
+
+// Exists checks if a transaction is saved in the database.
+func (t *ReadTx) Exists(ctx context.Context, hash string) (exists bool, err error) {
+	if err := t.tx.QueryRowContext(ctx, sqlSelectTXExists, hash).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// GetTX returns a transaction with all its data.
+// ErrNotFound is returned when the transaction doesn't exist.
+func (t *ReadTx) GetTX(ctx context.Context, hash string) (cosmosclient.TX, error) {
+	txs, err := fetchTXs(ctx, t.tx, sqlTXByHashClauses, hash)
+	if err != nil {
+		return cosmosclient.TX{}, err
+	}
+
+	if len(txs) == 0 {
+		return cosmosclient.TX{}, fmt.Errorf("%w: %s", ErrNotFound, hash)
+	}
+
+	return txs[0], nil
+}
+
+// GetTXAtHeightIndex returns the transaction with a specific index within a block.
+// ErrNotFound is returned when the block doesn't have a transaction with the index.
+func (t *ReadTx) GetTXAtHeightIndex(ctx context.Context, height int64, index uint32) (cosmosclient.TX, error) {
+	txs, err := fetchTXs(ctx, t.tx, sqlTXAtHeightIndexClauses, height, index)
+	if err != nil {
+		return cosmosclient.TX{}, err
+	}
+
+	if len(txs) == 0 {
+		return cosmosclient.TX{}, fmt.Errorf("%w: height %d index %d", ErrNotFound, height, index)
+	}
+
+	return txs[0], nil
+}
+
+// GetFailedTXs returns the failed transactions within a block height range.
+// The range includes both the "from" and "to" block heights.
+func (t *ReadTx) GetFailedTXs(ctx context.Context, from, to int64) ([]cosmosclient.TX, error) {
+	if err := validateHeightRange(from, to); err != nil {
+		return nil, err
+	}
+
+	return fetchTXs(ctx, t.tx, sqlFailedTXsByHeightRangeClauses, from, to)
+}
+
+// GetRecentTXs returns the most recent transactions.
+// Transactions are sorted from newest to oldest and up to "n" transactions are returned.
+// The number of transactions is limited to MaxRecentTXs to avoid reading too many of them.
+func (t *ReadTx) GetRecentTXs(ctx context.Context, n int) ([]cosmosclient.TX, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	return fetchTXs(ctx, t.tx, sqlRecentTXsClauses, limitRecentTXs(n))
+}
+
+// GetTXsByHeights returns the transactions of a list of block heights.
+// Transactions are sorted by block height and index.
+// No transactions are returned for an empty list.
+func (t *ReadTx) GetTXsByHeights(ctx context.Context, heights []int64) ([]cosmosclient.TX, error) {
+	if len(heights) == 0 {
+		return nil, nil
+	}
+
+	return fetchTXs(ctx, t.tx, sqlTXsByHeightsClauses, pq.Array(heights))
+}
+
+// GetTXsByTimeRange returns the transactions with a block time within a time range.
+// Transactions are sorted chronologically and the range includes both the start
+// and end times. All the transactions within the range are returned when the limit
+// is zero.
+func (t *ReadTx) GetTXsByTimeRange(ctx context.Context, start, end time.Time, limit int) ([]cosmosclient.TX, error) {
+	clauses, args, err := timeRangeQuery(start, end, limit, t.epochTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchTXs(ctx, t.tx, clauses, args...)
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTx(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	adapter := Adapter{db: db}
+	tx := createTestTX(t)
+
+	jsonResTX, err := json.Marshal(tx.Raw)
+	require.NoError(t, err)
+
+	// Arrange: Database mock and expectations
+	mock.ExpectBegin()
+	mock.
+		ExpectQuery(sqlSelectBlockHeight).
+		WillReturnRows(sqlmock.NewRows([]string{"height"}).AddRow(tx.Raw.Height))
+	mock.
+		ExpectQuery(fmt.Sprintf(tplSelectTXsSQL, sqlRecentTXsClauses)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"block_time", "data"}).AddRow(tx.BlockTime, jsonResTX))
+	mock.ExpectRollback()
+
+	// Act
+	readTx, err := adapter.ReadTx(ctx)
+	require.NoError(t, err)
+
+	height, err := readTx.GetLatestHeight(ctx)
+	require.NoError(t, err)
+
+	txs, err := readTx.GetRecentTXs(ctx, 1)
+	require.NoError(t, err)
+
+	err = readTx.Close()
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, tx.Raw.Height, height)
+	require.Len(t, txs, 1)
+	require.Equal(t, tx.Raw.TxResult.Events, txs[0].Raw.TxResult.Events)
+}
+
+func TestReadTxLookups(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	adapter := Adapter{db: db}
+	tx := createTestTX(t)
+	hash := tx.Raw.Hash.String()
+
+	jsonResTX, err := json.Marshal(tx.Raw)
+	require.NoError(t, err)
+
+	// Arrange: Database mock and expectations
+	mock.ExpectBegin()
+	mock.
+		ExpectQuery(fmt.Sprintf(tplSelectTXsSQL, sqlTXByHashClauses)).
+		WithArgs(hash).
+		WillReturnRows(sqlmock.NewRows([]string{"block_time", "data"}).AddRow(tx.BlockTime, jsonResTX))
+	mock.
+		ExpectQuery(sqlSelectTXHashesByHeightRange).
+		WithArgs(int64(1), int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow(hash))
+	mock.
+		ExpectQuery(sqlSelectChainHeights).
+		WillReturnRows(sqlmock.NewRows([]string{"chain_id", "height"}).AddRow("mars", 42))
+	mock.ExpectRollback()
+
+	// Act
+	readTx, err := adapter.ReadTx(ctx)
+	require.NoError(t, err)
+
+	got, err := readTx.GetTX(ctx, hash)
+	require.NoError(t, err)
+
+	hashes, err := readTx.GetTXHashesByHeightRange(ctx, 1, 10)
+	require.NoError(t, err)
+
+	heights, err := readTx.GetLatestHeightsByChain(ctx)
+	require.NoError(t, err)
+
+	err = readTx.Close()
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, tx.Raw.TxResult.Events, got.Raw.TxResult.Events)
+	require.Equal(t, []string{hash}, hashes)
+	require.Equal(t, map[string]int64{"mars": 42}, heights)
+}
+
+func TestReadTxClosed(t *testing.T) {
+	_, err := Adapter{}.ReadTx(context.Background())
+
+	require.ErrorIs(t, err, ErrClosed)
+}
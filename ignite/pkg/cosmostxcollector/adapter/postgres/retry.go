@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"syscall"
+	"time"
+)
+
+// DefaultRetryBackoff defines the default time to wait before the first retry.
+const DefaultRetryBackoff = 100 * time.Millisecond
+
+// errCodeSerializationFailure is the SQLSTATE code returned when concurrent
+// transactions can't be serialized using the configured isolation level.
+const errCodeSerializationFailure = "40001"
+
+// RetryPolicy defines how database operations are retried after connection errors.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times an operation is retried.
+	// Operations are not retried when the value is zero.
+	MaxRetries int
+
+	// Backoff is the time to wait before the first retry.
+	// The time is doubled after each retry.
+	Backoff time.Duration
+
+	// MaxBackoff is the maximum time to wait between retries.
+	// There is no maximum when the value is zero.
+	MaxBackoff time.Duration
+}
+
+// WithRetryPolicy configures the policy to retry operations after connection errors.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(a *Adapter) {
+		a.retryPolicy = p
+	}
+}
+
+// withRetry calls a function and calls it again after connection errors or serialization
+// failures until it succeeds or the maximum number of retries is reached.
+// The result is recorded as the status of the last database operation.
+func (a Adapter) withRetry(ctx context.Context, fn func() error) (err error) {
+	defer func() { a.recordStatus(err) }()
+
+	p := a.retryPolicy
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+
+	err = fn()
+	for i := 0; i < p.MaxRetries && isRetryable(err); i++ {
+		t := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+
+		if backoff *= 2; p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+
+		err = fn()
+	}
+
+	return err
+}
+
+// commitError is returned when a database transaction fails to commit.
+// The result of the commit is unknown so the operation is not retried.
+type commitError struct {
+	err error
+}
+
+func (e commitError) Error() string {
+	return e.err.Error()
+}
+
+func (e commitError) Unwrap() error {
+	return e.err
+}
+
+// commit commits a database transaction and wraps the error when it fails.
+func commit(sqlTx *sql.Tx) error {
+	if err := sqlTx.Commit(); err != nil {
+		return commitError{err}
+	}
+
+	return nil
+}
+
+// isRetryable checks if an operation can be retried after an error.
+func isRetryable(err error) bool {
+	if errors.As(err, &commitError{}) {
+		return false
+	}
+
+	return isConnError(err) || ErrorCode(err) == errCodeSerializationFailure
+}
+
+// isConnError checks if an error is caused by a database connection failure.
+func isConnError(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, syscall.ECONNREFUSED)
+}
@@ -0,0 +1,217 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRetry(t *testing.T) {
+	connRefused := &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+
+	cases := []struct {
+		name  string
+		err   error
+		calls int
+	}{
+		{
+			name:  "bad connection",
+			err:   driver.ErrBadConn,
+			calls: 3,
+		},
+		{
+			name:  "connection refused",
+			err:   fmt.Errorf("dial error: %w", connRefused),
+			calls: 3,
+		},
+		{
+			name:  "serialization failure",
+			err:   &pq.Error{Code: errCodeSerializationFailure},
+			calls: 3,
+		},
+		{
+			name:  "commit failure",
+			err:   commitError{driver.ErrBadConn},
+			calls: 1,
+		},
+		{
+			name:  "other error",
+			err:   errors.New("foo"),
+			calls: 1,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			adapter := Adapter{retryPolicy: RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond}}
+			calls := 0
+
+			// Act
+			err := adapter.withRetry(context.Background(), func() error {
+				calls++
+				return tt.err
+			})
+
+			// Assert
+			require.ErrorIs(t, err, tt.err)
+			require.Equal(t, tt.calls, calls)
+		})
+	}
+}
+
+func TestWithRetrySuccess(t *testing.T) {
+	// Arrange
+	adapter := Adapter{retryPolicy: RetryPolicy{MaxRetries: 5, Backoff: time.Millisecond}}
+	calls := 0
+
+	// Act
+	err := adapter.withRetry(context.Background(), func() error {
+		if calls++; calls < 2 {
+			return driver.ErrBadConn
+		}
+
+		return nil
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+}
+
+func TestWithRetryDisabled(t *testing.T) {
+	calls := 0
+
+	err := Adapter{}.withRetry(context.Background(), func() error {
+		calls++
+		return driver.ErrBadConn
+	})
+
+	require.ErrorIs(t, err, driver.ErrBadConn)
+	require.Equal(t, 1, calls)
+}
+
+func TestGetLatestHeightWithRetry(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db, retryPolicy: RetryPolicy{MaxRetries: 1, Backoff: time.Millisecond}}
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectBlockHeight).
+		WillReturnError(syscall.ECONNREFUSED)
+	mock.
+		ExpectQuery(sqlSelectBlockHeight).
+		WillReturnRows(sqlmock.NewRows([]string{"height"}).AddRow(42))
+
+	// Act
+	height, err := adapter.GetLatestHeight(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.EqualValues(t, 42, height)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReadsWithRetry(t *testing.T) {
+	cases := []struct {
+		name    string
+		sql     string
+		columns []string
+		values  []driver.Value
+		read    func(context.Context, Adapter) error
+	}{
+		{
+			name:    "TX log",
+			sql:     sqlSelectTXLog,
+			columns: []string{"raw_log"},
+			values:  []driver.Value{"log"},
+			read: func(ctx context.Context, a Adapter) error {
+				_, err := a.GetTXLog(ctx, "hash")
+				return err
+			},
+		},
+		{
+			name:    "missing heights",
+			sql:     sqlSelectMissingHeights,
+			columns: []string{"height"},
+			values:  []driver.Value{2},
+			read: func(ctx context.Context, a Adapter) error {
+				_, err := a.MissingHeights(ctx, 1, 3)
+				return err
+			},
+		},
+		{
+			name:    "blocks by proposer",
+			sql:     sqlSelectBlocksByProposer,
+			columns: []string{"height", "tx_count", "saved_at"},
+			values:  []driver.Value{1, 2, nil},
+			read: func(ctx context.Context, a Adapter) error {
+				_, err := a.GetBlocksByProposer(ctx, "proposer", 10)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			db, mock := createMatchEqualSQLMock(t)
+			defer db.Close()
+
+			adapter := Adapter{db: db, retryPolicy: RetryPolicy{MaxRetries: 1, Backoff: time.Millisecond}}
+
+			// Arrange: Database mock and expectations
+			mock.
+				ExpectQuery(tt.sql).
+				WillReturnError(syscall.ECONNREFUSED)
+			mock.
+				ExpectQuery(tt.sql).
+				WillReturnRows(sqlmock.NewRows(tt.columns).AddRow(tt.values...))
+
+			// Act
+			err := tt.read(context.Background(), adapter)
+
+			// Assert
+			require.NoError(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSaveOneCommitErrorIsNotRetried(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db, retryPolicy: RetryPolicy{MaxRetries: 1, Backoff: time.Millisecond}}
+	insertResult := sqlmock.NewResult(0, 1)
+
+	// Arrange: Database mock and expectations for a save where the commit
+	// fails with a connection error after the changes were sent.
+	mock.ExpectBegin()
+	mock.ExpectExec(sqlInsertRawTX).WillReturnResult(insertResult)
+	mock.ExpectQuery(sqlInsertTX).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(sqlInsertEvent).WillReturnRows(sqlmock.NewRows([]string{"event_id"}).AddRow(1))
+	mock.ExpectExec(sqlInsertEventAttr).WillReturnResult(insertResult)
+	mock.ExpectCommit().WillReturnError(driver.ErrBadConn)
+
+	// Act
+	err := adapter.SaveOne(context.Background(), createTestTX(t))
+
+	// Assert
+	require.ErrorIs(t, err, driver.ErrBadConn)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
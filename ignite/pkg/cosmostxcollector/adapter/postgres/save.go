@@ -0,0 +1,750 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+const (
+	valueTypeArray   = "array"
+	valueTypeBoolean = "boolean"
+	valueTypeNull    = "null"
+	valueTypeNumber  = "number"
+	valueTypeObject  = "object"
+	valueTypeString  = "string"
+
+	sqlInsertTX = `
+		INSERT INTO tx (hash, index, height, block_time, block_time_ms, code, codespace, chain_id, created_at, raw_log)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id
+	`
+	sqlInsertEvent = `
+		INSERT INTO event (tx_hash, type, index)
+		VALUES ($1, $2, $3) RETURNING id
+	`
+	sqlInsertEventAttr = `
+		INSERT INTO attribute (event_id, name, value, value_type, index)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	sqlInsertBlock = `
+		INSERT INTO block (height, tx_count, proposer, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	sqlInsertRawTX = `
+		INSERT INTO raw_tx (hash, data)
+		VALUES ($1, $2)
+	`
+	sqlTXSavepoint         = "SAVEPOINT tx"
+	sqlTXRollbackSavepoint = "ROLLBACK TO SAVEPOINT tx"
+	sqlTXReleaseSavepoint  = "RELEASE SAVEPOINT tx"
+)
+
+// SaveResult contains the result of saving a list of transactions.
+type SaveResult struct {
+	// TXIDs contains the database generated IDs of the saved transactions.
+	// The IDs are in the same order as the saved transactions.
+	TXIDs []int64
+
+	// Errors contains the errors of the transactions that couldn't be saved.
+	// It is only used when the adapter continues saving after errors, or when
+	// the transactions are saved within savepoints.
+	Errors []SaveError
+
+	// PrepareDuration is the time spent preparing the insert statements.
+	PrepareDuration time.Duration
+
+	// InsertDuration is the time spent inserting the transactions.
+	InsertDuration time.Duration
+
+	// CommitDuration is the time spent committing the database transactions.
+	CommitDuration time.Duration
+
+	// SkippedAttributes contains the event attributes that were not saved.
+	// It is only used when the adapter skips the attributes that can't be saved.
+	SkippedAttributes []SkippedAttribute
+}
+
+// add adds the saved transactions, the errors and the durations of another result.
+func (r *SaveResult) add(other SaveResult) {
+	r.TXIDs = append(r.TXIDs, other.TXIDs...)
+	r.Errors = append(r.Errors, other.Errors...)
+	r.PrepareDuration += other.PrepareDuration
+	r.InsertDuration += other.InsertDuration
+	r.CommitDuration += other.CommitDuration
+	r.SkippedAttributes = append(r.SkippedAttributes, other.SkippedAttributes...)
+}
+
+func (a Adapter) Save(ctx context.Context, txs []cosmosclient.TX) error {
+	if a.writes != nil {
+		return a.writes.add(ctx, txs)
+	}
+
+	return a.saveUnbuffered(ctx, txs)
+}
+
+// saveUnbuffered saves a list of transactions without using the write buffer.
+func (a Adapter) saveUnbuffered(ctx context.Context, txs []cosmosclient.TX) error {
+	_, err := a.SaveWithResult(ctx, txs)
+	return err
+}
+
+// SaveWithResult saves a list of transactions into the database.
+// The result contains the IDs generated by the database for the saved transactions.
+// Transactions are saved in more than one database transaction when they exceed the
+// maximum batch size in bytes, in which case the result contains the IDs of the
+// transactions that were saved before an error.
+func (a Adapter) SaveWithResult(ctx context.Context, txs []cosmosclient.TX) (SaveResult, error) {
+	r, err := a.saveBatches(ctx, txs)
+
+	// The tables are analyzed even when only some of the transactions were saved
+	a.autoAnalyze(txs, r)
+
+	return r, err
+}
+
+// saveBatches saves a list of transactions in batches.
+func (a Adapter) saveBatches(ctx context.Context, txs []cosmosclient.TX) (SaveResult, error) {
+	if a.continueOnError {
+		return a.saveEach(ctx, txs)
+	}
+
+	var (
+		r      SaveResult
+		offset int
+	)
+
+	for _, batch := range a.splitBatches(txs) {
+		var br SaveResult
+
+		err := a.withRetry(ctx, func() (err error) {
+			br, err = a.saveWithResult(ctx, batch)
+			return err
+		})
+		if err != nil {
+			// The index of the transaction must be relative to the saved transactions
+			var saveErr SaveError
+			if errors.As(err, &saveErr) {
+				saveErr.Index += offset
+				err = saveErr
+			}
+
+			return r, err
+		}
+
+		// The index of the skipped transactions must be relative to the saved transactions
+		for i := range br.Errors {
+			br.Errors[i].Index += offset
+		}
+
+		r.add(br)
+		offset += len(batch)
+	}
+
+	return r, nil
+}
+
+// saveEach saves each transaction within its own database transaction.
+// The transactions that can't be saved are skipped and their errors are added to the result.
+func (a Adapter) saveEach(ctx context.Context, txs []cosmosclient.TX) (SaveResult, error) {
+	var r SaveResult
+
+	for i, tx := range txs {
+		var tr SaveResult
+
+		err := a.withRetry(ctx, func() (err error) {
+			tr, err = a.saveWithResult(ctx, []cosmosclient.TX{tx})
+			return err
+		})
+
+		// Stop saving when the operation is canceled or the adapter is closed
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return r, ctxErr
+		}
+
+		if errors.Is(err, ErrClosed) {
+			return r, err
+		}
+
+		if err != nil {
+			r.Errors = append(r.Errors, newSaveError(i, tx, err))
+			continue
+		}
+
+		r.add(tr)
+	}
+
+	return r, nil
+}
+
+// newSaveError returns the error of a transaction that can't be saved.
+// The index of the error is always the index of the transaction.
+func newSaveError(i int, tx cosmosclient.TX, err error) SaveError {
+	saveErr := SaveError{Index: i, Err: err}
+	if !errors.As(err, &saveErr) && tx.Raw != nil {
+		saveErr.Hash = tx.Raw.Hash.String()
+	}
+
+	saveErr.Index = i
+
+	return saveErr
+}
+
+// splitBatches splits a list of transactions into batches where the size of the attribute
+// values of each batch doesn't exceed the maximum batch size in bytes.
+// A batch always contains at least one transaction so transactions with attribute
+// values larger than the maximum are saved within their own batch.
+func (a Adapter) splitBatches(txs []cosmosclient.TX) [][]cosmosclient.TX {
+	if a.maxBatchBytes <= 0 || len(txs) == 0 {
+		return [][]cosmosclient.TX{txs}
+	}
+
+	var (
+		batches [][]cosmosclient.TX
+		start   int
+		size    int
+	)
+
+	for i, tx := range txs {
+		n := attributeValuesSize(tx)
+		if i > start && size+n > a.maxBatchBytes {
+			batches = append(batches, txs[start:i])
+			start, size = i, 0
+		}
+
+		size += n
+	}
+
+	return append(batches, txs[start:])
+}
+
+// attributeValuesSize returns the size in bytes of the event attribute values of a transaction.
+func attributeValuesSize(tx cosmosclient.TX) (size int) {
+	if tx.Raw == nil {
+		return 0
+	}
+
+	for _, e := range tx.Raw.TxResult.Events {
+		for _, attr := range e.Attributes {
+			size += len(attr.Value)
+		}
+	}
+
+	return size
+}
+
+func (a Adapter) saveWithResult(ctx context.Context, txs []cosmosclient.TX) (SaveResult, error) {
+	return a.saveTXs(ctx, txs, nil)
+}
+
+// saveTXs saves a list of transactions within a single database transaction.
+// The optional function is called within the same database transaction after
+// the transactions are saved, and before they are committed.
+func (a Adapter) saveTXs(ctx context.Context, txs []cosmosclient.TX, fn func(*sql.Tx) error) (SaveResult, error) {
+	// Blocks are always saved atomically so their transactions are not saved within savepoints
+	savepoints := a.txSavepoints && fn == nil
+	if !savepoints {
+		for i, tx := range txs {
+			if err := a.validateTX(i, tx); err != nil {
+				return SaveResult{}, err
+			}
+		}
+	}
+
+	db, err := a.getDB()
+	if err != nil {
+		return SaveResult{}, err
+	}
+
+	merge, err := a.useMerge(ctx)
+	if err != nil {
+		return SaveResult{}, err
+	}
+
+	// Start a transaction
+	sqlTx, err := db.BeginTx(ctx, a.txOptions())
+	if err != nil {
+		return SaveResult{}, err
+	}
+
+	// Rollback is only called when the transaction is not committed
+	var committed bool
+	defer rollback(sqlTx, &committed)
+
+	// Prepare insert statements to speed up "bulk" saving times
+	start := time.Now()
+	preparedTXStmt, err := sqlTx.PrepareContext(ctx, a.getTXInsertSQL(merge))
+	if err != nil {
+		return SaveResult{}, err
+	}
+
+	defer preparedTXStmt.Close()
+
+	var txStmt stmt = preparedTXStmt
+	if merge {
+		txStmt = mergeStmt{preparedTXStmt, sqlTx}
+	}
+
+	evtStmt, err := sqlTx.PrepareContext(ctx, sqlInsertEvent)
+	if err != nil {
+		return SaveResult{}, err
+	}
+
+	defer evtStmt.Close()
+
+	attrStmt, err := sqlTx.PrepareContext(ctx, a.getAttrInsertSQL())
+	if err != nil {
+		return SaveResult{}, err
+	}
+
+	defer attrStmt.Close()
+
+	// All the transactions are saved within the context of the same database
+	// transactions and because of that either all block transactions are
+	// saved or none of them.
+	result := SaveResult{
+		TXIDs:           make([]int64, 0, len(txs)),
+		PrepareDuration: time.Since(start),
+	}
+
+	start = time.Now()
+
+	saveTX := func(tx cosmosclient.TX) (int64, error) {
+		if err := a.saveRawTX(ctx, sqlTx, tx.Raw); err != nil {
+			return 0, err
+		}
+
+		if err := a.deleteTXEvents(ctx, sqlTx, tx.Raw.Hash.String()); err != nil {
+			return 0, err
+		}
+
+		id, skipped, err := a.saveTX(ctx, txStmt, evtStmt, a.getAttrStmt(sqlTx, attrStmt), tx)
+		if err != nil {
+			return 0, err
+		}
+
+		result.SkippedAttributes = append(result.SkippedAttributes, skipped...)
+
+		return id, nil
+	}
+
+	saved := txs
+	if savepoints {
+		saved = make([]cosmosclient.TX, 0, len(txs))
+	}
+
+	for i, tx := range txs {
+		if !savepoints {
+			id, err := saveTX(tx)
+			if err != nil {
+				return SaveResult{}, err
+			}
+
+			result.TXIDs = append(result.TXIDs, id)
+			continue
+		}
+
+		id, saveErr, err := saveWithinSavepoint(ctx, sqlTx, func() (int64, error) {
+			if err := a.validateTX(i, tx); err != nil {
+				return 0, err
+			}
+
+			return saveTX(tx)
+		})
+		if err != nil {
+			return SaveResult{}, err
+		}
+
+		if saveErr != nil {
+			result.Errors = append(result.Errors, newSaveError(i, tx, saveErr))
+			continue
+		}
+
+		saved = append(saved, tx)
+		result.TXIDs = append(result.TXIDs, id)
+	}
+
+	if fn != nil {
+		if err := fn(sqlTx); err != nil {
+			return SaveResult{}, err
+		}
+	}
+
+	if a.notify && len(saved) > 0 {
+		if err := notifyTXs(ctx, sqlTx, saved...); err != nil {
+			return SaveResult{}, err
+		}
+	}
+
+	result.InsertDuration = time.Since(start)
+
+	if err := a.runPreCommit(ctx, saved); err != nil {
+		return SaveResult{}, err
+	}
+
+	start = time.Now()
+
+	// The transaction is done after calling commit even when it fails
+	committed = true
+
+	if err := commit(sqlTx); err != nil {
+		return SaveResult{}, err
+	}
+
+	result.CommitDuration = time.Since(start)
+
+	a.runPostCommit(ctx, saved)
+	a.runAttributesSkipped(ctx, result.SkippedAttributes)
+
+	return result, nil
+}
+
+// saveWithinSavepoint calls a function that saves a transaction within a savepoint.
+// The changes are rolled back to the savepoint when the function fails, which allows
+// the database transaction to continue. The error of the function is returned as the
+// save error, and the error is only returned when the savepoint commands fail.
+func saveWithinSavepoint(ctx context.Context, sqlTx *sql.Tx, fn func() (int64, error)) (id int64, saveErr, err error) {
+	if _, err := sqlTx.ExecContext(ctx, sqlTXSavepoint); err != nil {
+		return 0, nil, fmt.Errorf("transaction savepoint failed: %w", err)
+	}
+
+	if id, saveErr = fn(); saveErr != nil {
+		if _, err := sqlTx.ExecContext(ctx, sqlTXRollbackSavepoint); err != nil {
+			return 0, nil, fmt.Errorf("transaction savepoint failed: %w", err)
+		}
+
+		return 0, saveErr, nil
+	}
+
+	if _, err := sqlTx.ExecContext(ctx, sqlTXReleaseSavepoint); err != nil {
+		return 0, nil, fmt.Errorf("transaction savepoint failed: %w", err)
+	}
+
+	return id, nil, nil
+}
+
+// SaveBlock saves all the transactions of a block and marks the block as saved.
+// The transactions and the block are saved within the same database transaction,
+// so either the whole block is saved or none of it. Blocks with transactions that
+// are not marked as saved were only partially saved and must be saved again.
+// All the transactions must belong to the block.
+// The block proposer is saved as unknown, SaveBlockWithProposer should be used to save it.
+func (a Adapter) SaveBlock(ctx context.Context, height int64, txs []cosmosclient.TX) error {
+	return a.SaveBlockWithProposer(ctx, height, "", txs)
+}
+
+// SaveBlockWithProposer saves all the transactions of a block and marks the block as saved
+// together with the address of the validator that proposed the block.
+// The proposer is saved as unknown when the address is empty.
+func (a Adapter) SaveBlockWithProposer(ctx context.Context, height int64, proposer string, txs []cosmosclient.TX) error {
+	for i, tx := range txs {
+		if tx.Raw != nil && tx.Raw.Height != height {
+			return SaveError{
+				Index: i,
+				Hash:  tx.Raw.Hash.String(),
+				Err:   fmt.Errorf("%w: expected %d, got %d", ErrInvalidBlockHeight, height, tx.Raw.Height),
+			}
+		}
+	}
+
+	saveBlock := func(sqlTx *sql.Tx) error {
+		p := sql.NullString{String: proposer, Valid: proposer != ""}
+		if _, err := sqlTx.ExecContext(ctx, sqlInsertBlock, height, len(txs), p, a.now()); err != nil {
+			return fmt.Errorf("error saving block %d: %w", height, err)
+		}
+
+		return nil
+	}
+
+	return a.withRetry(ctx, func() error {
+		_, err := a.saveTXs(ctx, txs, saveBlock)
+		return err
+	})
+}
+
+// SaveOne saves a single transaction into the database.
+// It is optimized for saving one transaction at a time, for example when
+// following new blocks, because the inserts are executed without preparing
+// the SQL statements first.
+func (a Adapter) SaveOne(ctx context.Context, tx cosmosclient.TX) error {
+	return a.withRetry(ctx, func() error {
+		return a.saveOne(ctx, tx)
+	})
+}
+
+func (a Adapter) saveOne(ctx context.Context, tx cosmosclient.TX) error {
+	if err := a.validateTX(0, tx); err != nil {
+		return err
+	}
+
+	db, err := a.getDB()
+	if err != nil {
+		return err
+	}
+
+	merge, err := a.useMerge(ctx)
+	if err != nil {
+		return err
+	}
+
+	sqlTx, err := db.BeginTx(ctx, a.txOptions())
+	if err != nil {
+		return err
+	}
+
+	// Rollback is only called when the transaction is not committed
+	var committed bool
+	defer rollback(sqlTx, &committed)
+
+	if err := a.saveRawTX(ctx, sqlTx, tx.Raw); err != nil {
+		return err
+	}
+
+	if err := a.deleteTXEvents(ctx, sqlTx, tx.Raw.Hash.String()); err != nil {
+		return err
+	}
+
+	var txStmt stmt = unpreparedStmt{sqlTx, a.getTXInsertSQL(merge)}
+	if merge {
+		txStmt = mergeStmt{txStmt, sqlTx}
+	}
+
+	evtStmt := unpreparedStmt{sqlTx, sqlInsertEvent}
+	attrStmt := a.getAttrStmt(sqlTx, unpreparedStmt{sqlTx, a.getAttrInsertSQL()})
+
+	_, skipped, err := a.saveTX(ctx, txStmt, evtStmt, attrStmt, tx)
+	if err != nil {
+		return err
+	}
+
+	if a.notify {
+		if err := notifyTXs(ctx, sqlTx, tx); err != nil {
+			return err
+		}
+	}
+
+	txs := []cosmosclient.TX{tx}
+	if err := a.runPreCommit(ctx, txs); err != nil {
+		return err
+	}
+
+	// The transaction is done after calling commit even when it fails
+	committed = true
+
+	if err := commit(sqlTx); err != nil {
+		return err
+	}
+
+	a.runPostCommit(ctx, txs)
+	a.runAttributesSkipped(ctx, skipped)
+
+	return nil
+}
+
+// txOptions returns the options for the database transactions.
+// No options are returned when the default isolation level is used.
+func (a Adapter) txOptions() *sql.TxOptions {
+	if a.isolationLevel == sql.LevelDefault {
+		return nil
+	}
+
+	return &sql.TxOptions{Isolation: a.isolationLevel}
+}
+
+// rollback rolls back a database transaction unless it is committed.
+// Rolling back a committed transaction fails with sql.ErrTxDone, which
+// is logged as an error by some instrumented drivers, so it is avoided.
+func rollback(sqlTx *sql.Tx, committed *bool) {
+	if !*committed {
+		sqlTx.Rollback()
+	}
+}
+
+func (a Adapter) saveRawTX(ctx context.Context, sqlTx *sql.Tx, rtx *ctypes.ResultTx) error {
+	hash := rtx.Hash.String()
+	raw, err := json.Marshal(rtx)
+	if err != nil {
+		return fmt.Errorf("failed to encode raw TX %s: %w", hash, err)
+	}
+
+	data, compressed, err := a.compressRawTX(raw)
+	if err != nil {
+		return fmt.Errorf("failed to compress raw TX %s: %w", hash, err)
+	}
+
+	if _, err := sqlTx.ExecContext(ctx, a.getRawTXInsertSQL(compressed), hash, data); err != nil {
+		return fmt.Errorf("error saving raw TX %s: %w", hash, err)
+	}
+
+	return nil
+}
+
+// stmt defines the interface for SQL statements used to save transactions.
+// It is implemented by prepared statements and by unpreparedStmt.
+type stmt interface {
+	ExecContext(ctx context.Context, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, args ...any) *sql.Row
+}
+
+// unpreparedStmt executes an SQL query within a database transaction without preparing it.
+type unpreparedStmt struct {
+	tx    *sql.Tx
+	query string
+}
+
+func (s unpreparedStmt) ExecContext(ctx context.Context, args ...any) (sql.Result, error) {
+	return s.tx.ExecContext(ctx, s.query, args...)
+}
+
+func (s unpreparedStmt) QueryRowContext(ctx context.Context, args ...any) *sql.Row {
+	return s.tx.QueryRowContext(ctx, s.query, args...)
+}
+
+// saveTX saves a transaction with its events and returns the ID of the saved transaction.
+// The attributes that are not saved are returned as skipped when bad attributes are skipped.
+func (a Adapter) saveTX(ctx context.Context, txStmt, evtStmt, attrStmt stmt, tx cosmosclient.TX) (int64, []SkippedAttribute, error) {
+	var id int64
+
+	tx, err := a.resolveBlockTime(ctx, tx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	hash := tx.Raw.Hash.String()
+	if id, err = insertTX(ctx, txStmt, a.txInsertArgs(tx)); err != nil {
+		return 0, nil, fmt.Errorf("error saving TX %s: %w", hash, err)
+	}
+
+	events, skipped, err := a.getEvents(tx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for _, evt := range events {
+		var evtID int
+
+		row := evtStmt.QueryRowContext(ctx, hash, evt.Type, evt.Index)
+		if err := row.Err(); err != nil {
+			return 0, nil, fmt.Errorf("error saving event '%s': %w", evt.Type, err)
+		}
+
+		if err := row.Scan(&evtID); err != nil {
+			return 0, nil, fmt.Errorf("error reading event ID: %w", err)
+		}
+
+		for i, attr := range evt.Attributes {
+			// The value type is only saved when type hints are enabled
+			var valueType sql.NullString
+			if a.typeHints {
+				valueType = sql.NullString{String: getValueType(attr.Value), Valid: true}
+			}
+
+			if _, err := attrStmt.ExecContext(ctx, evtID, attr.Key, attr.Value, valueType, evt.AttrIndexes[i]); err != nil {
+				// The attribute is rolled back to its savepoint when bad attributes are skipped
+				var spErr savepointError
+				if a.skipBadAttrs && !errors.As(err, &spErr) {
+					skipped = append(skipped, SkippedAttribute{
+						TXHash:    hash,
+						EventType: evt.Type,
+						Name:      attr.Key,
+						Err:       err,
+					})
+					continue
+				}
+
+				return 0, nil, fmt.Errorf("error saving event attr '%s.%s': %w", evt.Type, attr.Key, err)
+			}
+		}
+	}
+
+	return id, skipped, nil
+}
+
+// txInsertArgs returns the arguments to insert a transaction into the database.
+// Optional values are inserted as NULL when they are not available, so they can
+// be distinguished from zero values.
+func (a Adapter) txInsertArgs(tx cosmosclient.TX) []any {
+	// The block time is saved either as a timestamp or as an epoch time
+	var (
+		blockTime   sql.NullTime
+		blockTimeMs sql.NullInt64
+	)
+
+	// The block time is saved as NULL when it is not known
+	if !tx.BlockTime.IsZero() {
+		if a.epochTime {
+			blockTimeMs = sql.NullInt64{Int64: tx.BlockTime.UnixMilli(), Valid: true}
+		} else {
+			blockTime = sql.NullTime{Time: tx.BlockTime, Valid: true}
+		}
+	}
+
+	// The result code is always available because zero means success,
+	// but the codespace is only available for failed transactions
+	res := tx.Raw.TxResult
+	codespace := sql.NullString{String: res.Codespace, Valid: res.Codespace != ""}
+	chainID := sql.NullString{String: a.chainID, Valid: a.chainID != ""}
+
+	return []any{
+		tx.Raw.Hash.String(),
+		tx.Raw.Index,
+		tx.Raw.Height,
+		blockTime,
+		blockTimeMs,
+		res.Code,
+		codespace,
+		chainID,
+		a.now(),
+		res.Log,
+	}
+}
+
+// validateTX checks that a transaction can be saved.
+// The index is the position of the transaction in the list of saved transactions.
+func (a Adapter) validateTX(index int, tx cosmosclient.TX) error {
+	if tx.Raw == nil || len(tx.Raw.Hash) == 0 {
+		return SaveError{Index: index, Err: fmt.Errorf("%w: hash is empty", ErrInvalidTXHash)}
+	}
+
+	if a.strictValidation && len(tx.Raw.Hash) != tmhash.Size {
+		return SaveError{
+			Index: index,
+			Hash:  tx.Raw.Hash.String(),
+			Err:   fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidTXHash, tmhash.Size, len(tx.Raw.Hash)),
+		}
+	}
+
+	return nil
+}
+
+// getValueType returns the type of a JSON encoded value.
+// The type names are the same as the ones returned by the "jsonb_typeof" function.
+func getValueType(v []byte) string {
+	v = bytes.TrimSpace(v)
+	if len(v) == 0 {
+		return valueTypeNull
+	}
+
+	switch v[0] {
+	case '"':
+		return valueTypeString
+	case '{':
+		return valueTypeObject
+	case '[':
+		return valueTypeArray
+	case 't', 'f':
+		return valueTypeBoolean
+	case 'n':
+		return valueTypeNull
+	default:
+		return valueTypeNumber
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"io/fs"
+	"path"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -38,6 +40,18 @@ const (
 	`
 )
 
+// schemaFileNameRe matches valid schema file names.
+var schemaFileNameRe = regexp.MustCompile(`^\d+\.sql$`)
+
+// SchemaFile contains the SQL script of a schema version.
+type SchemaFile struct {
+	// Version is the version of the schema.
+	Version uint
+
+	// Content is the SQL script of the schema file.
+	Content []byte
+}
+
 // SchemasWalkFunc is the type of the function called by WalkFrom.
 type SchemasWalkFunc func(version uint64, script []byte) error
 
@@ -73,6 +87,126 @@ func (s Schemas) GetSchemaVersionSQL() string {
 	return fmt.Sprintf(tplSchemaVersionSQL, s.tableName)
 }
 
+// Validate checks that the schema files are valid.
+// The names of the schema files must be numeric and have the ".sql" extension,
+// and the schema versions must be contiguous starting from version one.
+func (s Schemas) Validate() error {
+	entries, err := fs.ReadDir(s.fs, SchemasDir)
+	if err != nil {
+		return fmt.Errorf("failed to read schemas: %w", err)
+	}
+
+	paths := map[uint64]string{}
+
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !schemaFileNameRe.MatchString(name) {
+			return fmt.Errorf("invalid schema file name '%s'", name)
+		}
+
+		version := extractSchemaVersion(name)
+		if version == 0 {
+			return fmt.Errorf("invalid schema file version '%s'", name)
+		}
+
+		if p, ok := paths[version]; ok {
+			return fmt.Errorf("schema files '%s' and '%s' have the same version", p, name)
+		}
+
+		paths[version] = name
+	}
+
+	for i, ver := range sortedSchemaVersions(paths) {
+		if want := uint64(i + 1); ver != want {
+			return fmt.Errorf("missing schema file for version %d", want)
+		}
+	}
+
+	return nil
+}
+
+// LatestVersion returns the latest schema version available.
+// Zero is returned when there are no schema files.
+func (s Schemas) LatestVersion() (uint, error) {
+	entries, err := fs.ReadDir(s.fs, SchemasDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schemas: %w", err)
+	}
+
+	var latest uint
+	for _, e := range entries {
+		if v := uint(extractSchemaVersion(e.Name())); v > latest {
+			latest = v
+		}
+	}
+
+	return latest, nil
+}
+
+// Files returns the schema files sorted by version.
+// The content of each file is the SQL script as it is in the file, without the
+// commands that are added to apply the schema within a database transaction.
+func (s Schemas) Files() ([]SchemaFile, error) {
+	entries, err := fs.ReadDir(s.fs, SchemasDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schemas: %w", err)
+	}
+
+	paths := map[uint64]string{}
+	for _, e := range entries {
+		p := path.Join(SchemasDir, e.Name())
+		version := extractSchemaVersion(p)
+		if e.IsDir() || version == 0 {
+			return nil, fmt.Errorf("invalid schema file name '%s'", p)
+		}
+
+		paths[version] = p
+	}
+
+	files := make([]SchemaFile, 0, len(paths))
+	for _, ver := range sortedSchemaVersions(paths) {
+		content, err := fs.ReadFile(s.fs, paths[ver])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema '%s': %w", paths[ver], err)
+		}
+
+		files = append(files, SchemaFile{Version: uint(ver), Content: content})
+	}
+
+	return files, nil
+}
+
+// Dump returns the SQL scripts of all the schema files as a single SQL script.
+// The scripts are sorted by version and each one starts with a comment with its version.
+// It allows managing the schema with external migration tools.
+func (s Schemas) Dump() (string, error) {
+	files, err := s.Files()
+	if err != nil {
+		return "", err
+	}
+
+	return dumpSchemaFiles(files), nil
+}
+
+// dumpSchemaFiles returns the SQL scripts of a list of schema files as a single SQL script.
+func dumpSchemaFiles(files []SchemaFile) string {
+	var b strings.Builder
+	for i, f := range files {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "-- Schema version %d\n", f.Version)
+		b.Write(f.Content)
+
+		if !bytes.HasSuffix(f.Content, []byte("\n")) {
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
 // WalkFrom calls a function for SQL schemas starting from a specific version.
 // This is useful to apply newer schemas that are not yet applied.
 func (s Schemas) WalkFrom(fromVersion uint64, fn SchemasWalkFunc) error {
@@ -0,0 +1 @@
+ALTER TABLE tx ADD COLUMN raw_log TEXT;
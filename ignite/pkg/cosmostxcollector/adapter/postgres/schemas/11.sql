@@ -0,0 +1 @@
+ALTER TABLE attribute ADD COLUMN "index" SMALLINT;
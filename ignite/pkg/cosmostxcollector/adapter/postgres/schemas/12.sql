@@ -0,0 +1 @@
+ALTER TABLE tx DROP CONSTRAINT tx_block_time_check;
@@ -0,0 +1,3 @@
+ALTER TABLE raw_tx ALTER COLUMN data DROP NOT NULL;
+ALTER TABLE raw_tx ADD COLUMN data_gz BYTEA;
+ALTER TABLE raw_tx ADD CONSTRAINT raw_tx_data_check CHECK (data IS NOT NULL OR data_gz IS NOT NULL);
@@ -0,0 +1,3 @@
+ALTER TABLE tx ADD COLUMN id BIGSERIAL;
+
+CREATE UNIQUE INDEX tx_id_idx ON tx (id);
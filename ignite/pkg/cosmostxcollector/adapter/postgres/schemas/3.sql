@@ -0,0 +1,4 @@
+ALTER TABLE tx ADD COLUMN code BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE tx ADD COLUMN codespace VARCHAR;
+
+CREATE INDEX tx_failed_idx ON tx (height) WHERE code <> 0;
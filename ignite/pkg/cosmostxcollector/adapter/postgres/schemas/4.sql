@@ -0,0 +1 @@
+CREATE INDEX tx_block_time_idx ON tx (block_time);
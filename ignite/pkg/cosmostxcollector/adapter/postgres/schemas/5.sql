@@ -0,0 +1 @@
+ALTER TABLE attribute ADD COLUMN value_type VARCHAR;
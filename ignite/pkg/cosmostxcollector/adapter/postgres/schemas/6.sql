@@ -0,0 +1,5 @@
+ALTER TABLE tx ALTER COLUMN block_time DROP NOT NULL;
+ALTER TABLE tx ADD COLUMN block_time_ms BIGINT;
+ALTER TABLE tx ADD CONSTRAINT tx_block_time_check CHECK (block_time IS NOT NULL OR block_time_ms IS NOT NULL);
+
+CREATE INDEX tx_block_time_ms_idx ON tx (block_time_ms);
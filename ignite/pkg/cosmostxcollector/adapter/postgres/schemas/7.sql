@@ -0,0 +1,7 @@
+CREATE TABLE block (
+    height      BIGINT NOT NULL,
+    tx_count    INTEGER NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT block_pk PRIMARY KEY (height)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE tx ADD COLUMN chain_id VARCHAR;
+
+CREATE INDEX tx_chain_id_height_idx ON tx (chain_id, height);
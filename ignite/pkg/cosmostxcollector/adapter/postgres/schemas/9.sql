@@ -0,0 +1,3 @@
+ALTER TABLE block ADD COLUMN proposer VARCHAR;
+
+CREATE INDEX block_proposer_height_idx ON block (proposer, height);
@@ -103,6 +103,183 @@ func TestSchemasWalkOrder(t *testing.T) {
 	m.AssertExpectations(t)
 }
 
+func TestSchemasWalkVersionInsert(t *testing.T) {
+	// Arrange
+	fs := fstest.MapFS{
+		"schemas/1.sql": &fstest.MapFile{},
+		"schemas/2.sql": &fstest.MapFile{},
+		"schemas/3.sql": &fstest.MapFile{},
+	}
+	s := postgres.NewSchemas(fs, "")
+	inserts := map[uint64]string{}
+
+	// Act
+	err := s.WalkFrom(1, func(ver uint64, script []byte) error {
+		inserts[ver] = string(script)
+		return nil
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, inserts, 3)
+
+	for ver, script := range inserts {
+		require.Contains(t, script, fmt.Sprintf("INSERT INTO schema(version)\n\t\tVALUES(%d)", ver))
+	}
+}
+
+func TestSchemasLatestVersion(t *testing.T) {
+	// Arrange
+	fs := fstest.MapFS{
+		"schemas/1.sql":  &fstest.MapFile{},
+		"schemas/2.sql":  &fstest.MapFile{},
+		"schemas/10.sql": &fstest.MapFile{},
+	}
+	s := postgres.NewSchemas(fs, "")
+
+	// Act
+	v, err := s.LatestVersion()
+
+	// Assert
+	require.NoError(t, err)
+	require.EqualValues(t, 10, v)
+}
+
+func TestSchemasFiles(t *testing.T) {
+	// Arrange
+	fs := fstest.MapFS{
+		"schemas/1.sql":  &fstest.MapFile{Data: []byte("/* TEST-V1 */")},
+		"schemas/2.sql":  &fstest.MapFile{Data: []byte("/* TEST-V2 */")},
+		"schemas/10.sql": &fstest.MapFile{Data: []byte("/* TEST-V10 */")},
+	}
+	s := postgres.NewSchemas(fs, "")
+
+	// Act
+	files, err := s.Files()
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, []postgres.SchemaFile{
+		{Version: 1, Content: []byte("/* TEST-V1 */")},
+		{Version: 2, Content: []byte("/* TEST-V2 */")},
+		{Version: 10, Content: []byte("/* TEST-V10 */")},
+	}, files)
+}
+
+func TestSchemaFiles(t *testing.T) {
+	// Act
+	files, err := postgres.SchemaFiles()
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, files, int(postgres.ExpectedSchemaVersion()))
+
+	for i, f := range files {
+		require.EqualValues(t, i+1, f.Version)
+		require.NotEmpty(t, f.Content)
+	}
+}
+
+func TestSchemasDump(t *testing.T) {
+	// Arrange
+	fs := fstest.MapFS{
+		"schemas/1.sql":  &fstest.MapFile{Data: []byte("/* TEST-V1 */\n")},
+		"schemas/2.sql":  &fstest.MapFile{Data: []byte("/* TEST-V2 */")},
+		"schemas/10.sql": &fstest.MapFile{Data: []byte("/* TEST-V10 */")},
+	}
+	s := postgres.NewSchemas(fs, "")
+	want := `-- Schema version 1
+/* TEST-V1 */
+
+-- Schema version 2
+/* TEST-V2 */
+
+-- Schema version 10
+/* TEST-V10 */
+`
+
+	// Act
+	dump, err := s.Dump()
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, want, dump)
+}
+
+func TestDumpSchema(t *testing.T) {
+	// Act
+	dump, err := postgres.DumpSchema()
+
+	// Assert
+	require.NoError(t, err)
+	require.Contains(t, dump, fmt.Sprintf("-- Schema version %d\n", postgres.ExpectedSchemaVersion()))
+}
+
+func TestSchemasValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		files []string
+		err   string
+	}{
+		{
+			name:  "valid",
+			files: []string{"1.sql", "2.sql", "10.sql", "3.sql", "4.sql", "5.sql", "6.sql", "7.sql", "8.sql", "9.sql"},
+		},
+		{
+			name:  "backup file",
+			files: []string{"1.sql", "1.sql.bak"},
+			err:   "invalid schema file name '1.sql.bak'",
+		},
+		{
+			name:  "non numeric name",
+			files: []string{"init.sql", "1.sql"},
+			err:   "invalid schema file name 'init.sql'",
+		},
+		{
+			name:  "version zero",
+			files: []string{"0.sql", "1.sql"},
+			err:   "invalid schema file version '0.sql'",
+		},
+		{
+			name:  "duplicated version",
+			files: []string{"1.sql", "01.sql"},
+			err:   "schema files '01.sql' and '1.sql' have the same version",
+		},
+		{
+			name:  "missing first version",
+			files: []string{"2.sql", "3.sql"},
+			err:   "missing schema file for version 1",
+		},
+		{
+			name:  "version gap",
+			files: []string{"1.sql", "2.sql", "4.sql"},
+			err:   "missing schema file for version 3",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			fs := fstest.MapFS{}
+			for _, name := range tt.files {
+				fs["schemas/"+name] = &fstest.MapFile{}
+			}
+
+			s := postgres.NewSchemas(fs, "")
+
+			// Act
+			err := s.Validate()
+
+			// Assert
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestScriptBuilder(t *testing.T) {
 	// Arrange
 	s1 := "SCRIPT-1;"
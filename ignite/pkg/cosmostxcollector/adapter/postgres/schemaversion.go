@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+)
+
+const sqlSelectTableExists = `
+	SELECT to_regclass($1) IS NOT NULL
+`
+
+// getSchemaVersion returns the current schema version.
+// Zero is returned when the schema table doesn't exist.
+func getSchemaVersion(ctx context.Context, db rowQuerier, s Schemas) (v uint, err error) {
+	exists, err := schemaTableExists(ctx, db, s)
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	if err := db.QueryRowContext(ctx, s.GetSchemaVersionSQL()).Scan(&v); err != nil {
+		return 0, fmt.Errorf("failed to read current schema version: %w", err)
+	}
+
+	return v, nil
+}
+
+// schemaTableExists checks if the schema table exists in the database.
+func schemaTableExists(ctx context.Context, db rowQuerier, s Schemas) (exists bool, err error) {
+	if err := db.QueryRowContext(ctx, sqlSelectTableExists, s.tableName).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check schema table: %w", err)
+	}
+
+	return exists, nil
+}
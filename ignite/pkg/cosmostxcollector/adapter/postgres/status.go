@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+)
+
+// LastError returns the error of the last database operation that failed and the time of the failure.
+// The error is cleared when a later database operation succeeds, so a nil error means that the
+// last operation succeeded. The status is tracked for every operation that reads from or writes
+// to the database, including queries, aggregates, exports and diagnostics. Invalid arguments,
+// canceled operations and missing transactions are not considered failures.
+// A zero time and a nil error are returned when no operation failed.
+func (a Adapter) LastError() (time.Time, error) {
+	if a.status == nil {
+		return time.Time{}, nil
+	}
+
+	a.status.mu.Lock()
+	defer a.status.mu.Unlock()
+
+	return a.status.errTime, a.status.err
+}
+
+// status keeps the result of the last database operation.
+type status struct {
+	mu      sync.Mutex
+	errTime time.Time
+	err     error
+}
+
+// recordStatus keeps the error of a database operation or clears it when the operation succeeds.
+func (a Adapter) recordStatus(err error) {
+	if a.status == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrNotFound) || errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+
+	a.status.mu.Lock()
+	defer a.status.mu.Unlock()
+
+	if err == nil {
+		a.status.errTime, a.status.err = time.Time{}, nil
+	} else {
+		a.status.errTime, a.status.err = a.now(), err
+	}
+}
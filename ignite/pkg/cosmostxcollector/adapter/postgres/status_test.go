@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ignite/cli/ignite/pkg/cosmostxcollector/query"
+)
+
+func TestLastError(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	now := time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC)
+	wantErr := errors.New("connection lost")
+	adapter := Adapter{
+		db:     db,
+		status: &status{},
+		clock:  func() time.Time { return now },
+	}
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectTXExists).
+		WithArgs("AB").
+		WillReturnError(wantErr)
+	mock.
+		ExpectQuery(sqlSelectTXExists).
+		WithArgs("AB").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	// Act
+	_, err := adapter.Exists(ctx, "AB")
+	require.ErrorIs(t, err, wantErr)
+
+	errTime, lastErr := adapter.LastError()
+
+	// Assert
+	require.Equal(t, now, errTime)
+	require.ErrorIs(t, lastErr, wantErr)
+
+	// Act: The error is cleared after a successful operation
+	_, err = adapter.Exists(ctx, "AB")
+	require.NoError(t, err)
+
+	errTime, lastErr = adapter.LastError()
+
+	// Assert
+	require.True(t, errTime.IsZero())
+	require.NoError(t, lastErr)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLastErrorIgnoresCanceled(t *testing.T) {
+	// Arrange
+	adapter := Adapter{status: &status{}}
+
+	// Act
+	adapter.recordStatus(context.Canceled)
+	adapter.recordStatus(ErrNotFound)
+	errTime, err := adapter.LastError()
+
+	// Assert
+	require.True(t, errTime.IsZero())
+	require.NoError(t, err)
+}
+
+func TestLastErrorWithQueryEvents(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	wantErr := errors.New("connection lost")
+	adapter := Adapter{db: db, status: &status{}}
+	q := query.NewEventQuery()
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(parseEventQuery(q, "attribute")).
+		WillReturnError(wantErr)
+
+	// Act
+	_, err := adapter.QueryEvents(context.Background(), q)
+	require.ErrorIs(t, err, wantErr)
+
+	_, lastErr := adapter.LastError()
+
+	// Assert
+	require.ErrorIs(t, lastErr, wantErr)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
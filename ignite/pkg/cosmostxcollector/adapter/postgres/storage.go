@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+const (
+	sqlSelectTableSizes = `
+		SELECT name, pg_total_relation_size(to_regclass(name))
+		FROM unnest($1::text[]) AS name
+		WHERE to_regclass(name) IS NOT NULL
+	`
+	sqlSelectHeaviestTXs = `
+		SELECT tx.hash, tx.height, COUNT(*) AS count
+		FROM tx
+			INNER JOIN event ON tx.hash = event.tx_hash
+			INNER JOIN attribute ON event.id = attribute.event_id
+		WHERE tx.height BETWEEN $1 AND $2
+		GROUP BY tx.hash, tx.height
+		ORDER BY count DESC, tx.hash
+		LIMIT $3
+	`
+)
+
+// sizeTables contains the names of the tables that are included in the table sizes.
+var sizeTables = []string{"tx", "event", "attribute", "raw_tx"}
+
+// managedTables contains the names of the tables created by the adapter schemas.
+var managedTables = []string{"tx", "event", "attribute", "raw_tx", "block"}
+
+// TXWeight contains the number of event attributes of a transaction.
+type TXWeight struct {
+	// Hash is the hash of the transaction.
+	Hash string
+
+	// Height is the block height of the transaction.
+	Height int64
+
+	// Attributes is the number of event attributes of the transaction.
+	Attributes int64
+}
+
+// TableSizes returns the disk space used by each table in bytes.
+// The size of each table includes the size of its indexes.
+// Tables that don't exist are not included.
+func (a Adapter) TableSizes(ctx context.Context) (sizes map[string]int64, err error) {
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.withRetry(ctx, func() (err error) {
+		sizes, err = fetchTableSizes(ctx, db)
+		return err
+	})
+
+	return sizes, err
+}
+
+// fetchTableSizes selects the disk space used by each table without retrying.
+func fetchTableSizes(ctx context.Context, db querier) (map[string]int64, error) {
+	rows, err := db.QueryContext(ctx, sqlSelectTableSizes, pq.Array(sizeTables))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table sizes: %w", err)
+	}
+
+	defer rows.Close()
+
+	sizes := make(map[string]int64, len(sizeTables))
+	for rows.Next() {
+		var (
+			name string
+			size int64
+		)
+
+		if err := rows.Scan(&name, &size); err != nil {
+			return nil, err
+		}
+
+		sizes[name] = size
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sizes, nil
+}
+
+// ManagedTables returns the names of the tables owned by the adapter.
+// It includes the tables created by the adapter schemas and the table that keeps
+// the applied schema versions, which allows tools to know which tables to back up
+// or drop without hardcoding their names. The names of the schema tables are fixed,
+// and only the name of the schema versions table depends on the schemas prefix.
+func (a Adapter) ManagedTables() []string {
+	tables := make([]string, 0, len(managedTables)+1)
+	tables = append(tables, managedTables...)
+
+	return append(tables, a.schemas.tableName)
+}
+
+// HeaviestTXs returns the transactions with the most event attributes within a block height range.
+// It allows finding spammy or pathological transactions that use most of the storage.
+// Up to "n" transactions are returned, sorted by the number of attributes in descending
+// order and then by hash, and transactions without attributes are not included.
+// The range includes both the "from" and "to" block heights.
+func (a Adapter) HeaviestTXs(ctx context.Context, from, to int64, n int) (weights []TXWeight, err error) {
+	if err := validateHeightRange(from, to); err != nil {
+		return nil, err
+	}
+
+	if n <= 0 {
+		return nil, nil
+	}
+
+	db, err := a.getReadDB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.withRetry(ctx, func() (err error) {
+		weights, err = fetchHeaviestTXs(ctx, db, from, to, n)
+		return err
+	})
+
+	return weights, err
+}
+
+// fetchHeaviestTXs selects the transactions with the most event attributes without retrying.
+func fetchHeaviestTXs(ctx context.Context, db querier, from, to int64, n int) (weights []TXWeight, err error) {
+	rows, err := db.QueryContext(ctx, sqlSelectHeaviestTXs, from, to, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read heaviest transactions: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var w TXWeight
+		if err := rows.Scan(&w.Hash, &w.Height, &w.Attributes); err != nil {
+			return nil, err
+		}
+
+		weights = append(weights, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return weights, nil
+}
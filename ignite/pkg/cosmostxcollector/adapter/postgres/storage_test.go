@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableSizes(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectTableSizes).
+		WithArgs(pq.Array(sizeTables)).
+		WillReturnRows(
+			sqlmock.
+				NewRows([]string{"name", "size"}).
+				AddRow("tx", 8192).
+				AddRow("attribute", 16384),
+		)
+
+	// Act
+	sizes, err := adapter.TableSizes(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, map[string]int64{"tx": 8192, "attribute": 16384}, sizes)
+}
+
+func TestManagedTables(t *testing.T) {
+	// Arrange
+	adapter := Adapter{schemaOptions: schemaOptions{schemas: NewSchemas(fsSchemas, "collector")}}
+
+	// Act
+	tables := adapter.ManagedTables()
+
+	// Assert
+	require.Equal(t, []string{"tx", "event", "attribute", "raw_tx", "block", "collector_schema"}, tables)
+}
+
+func TestManagedTablesMatchSchemas(t *testing.T) {
+	// Arrange
+	files, err := NewSchemas(fsSchemas, "").Files()
+	require.NoError(t, err)
+
+	var want []string
+	for _, f := range files {
+		for _, m := range regexp.MustCompile(`CREATE TABLE (\w+)`).FindAllSubmatch(f.Content, -1) {
+			want = append(want, string(m[1]))
+		}
+	}
+
+	// Assert: All the tables created by the schemas must be managed by the adapter
+	require.ElementsMatch(t, want, managedTables)
+}
+
+func TestHeaviestTXs(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+	want := []TXWeight{
+		{Hash: "F2564C78", Height: 3, Attributes: 120},
+		{Hash: "A1E78F25", Height: 7, Attributes: 15},
+	}
+
+	// Arrange: Database mock and expectations
+	mock.
+		ExpectQuery(sqlSelectHeaviestTXs).
+		WithArgs(int64(1), int64(10), 2).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"hash", "height", "count"}).
+				AddRow(want[0].Hash, want[0].Height, want[0].Attributes).
+				AddRow(want[1].Hash, want[1].Height, want[1].Attributes),
+		)
+
+	// Act
+	weights, err := adapter.HeaviestTXs(context.Background(), 1, 10, 2)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, want, weights)
+}
+
+func TestHeaviestTXsWithoutLimit(t *testing.T) {
+	weights, err := Adapter{}.HeaviestTXs(context.Background(), 1, 10, 0)
+
+	require.NoError(t, err)
+	require.Nil(t, weights)
+}
+
+func TestHeaviestTXsInvalidRange(t *testing.T) {
+	_, err := Adapter{}.HeaviestTXs(context.Background(), 10, 1, 5)
+
+	require.ErrorIs(t, err, ErrInvalidHeightRange)
+}
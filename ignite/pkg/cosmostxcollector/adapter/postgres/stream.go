@@ -0,0 +1,208 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+// DefaultBatchSize defines the default number of transactions to save in a single batch.
+const DefaultBatchSize = 100
+
+// WithBatchSize configures the number of transactions that SaveStream saves in a single batch.
+func WithBatchSize(size int) Option {
+	return func(a *Adapter) {
+		a.batchSize = size
+	}
+}
+
+// WithFlushInterval configures the maximum time that SaveStream waits to save a batch that is not full.
+func WithFlushInterval(d time.Duration) Option {
+	return func(a *Adapter) {
+		a.flushInterval = d
+	}
+}
+
+// WithBatchCommitted configures a function to call after each batch saved by SaveStream is committed.
+func WithBatchCommitted(fn func(lastHeight int64) error) Option {
+	return func(a *Adapter) {
+		a.batchCommitted = fn
+	}
+}
+
+// SaveStream saves the transactions received from a channel in batches.
+// The transactions of each batch are saved within the same database transaction.
+// A batch is saved when the number of received transactions reaches the batch size,
+// when the flush interval elapses, or when the channel is closed, in which case
+// the remaining transactions are saved before returning.
+// The transactions waiting to be saved can be saved at any time by calling Flush.
+func (a Adapter) SaveStream(ctx context.Context, tc <-chan []cosmosclient.TX) error {
+	var (
+		timer   *time.Timer
+		timeout <-chan time.Time
+	)
+
+	// Adapters created without the constructor don't have a shared buffer
+	buf := a.buffer
+	if buf == nil {
+		buf = &txBuffer{}
+	}
+
+	if a.flushInterval > 0 {
+		timer = time.NewTimer(a.flushInterval)
+		timeout = timer.C
+
+		defer timer.Stop()
+	}
+
+	flush := func() error {
+		if err := a.flushBatch(ctx, buf); err != nil {
+			return err
+		}
+
+		// Restart the flush interval after each save. The timer channel must be
+		// drained when the timer fired during the save of a full batch, otherwise
+		// the next batch would be saved as soon as the stream starts waiting.
+		if timer != nil {
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+
+			timer.Reset(a.flushInterval)
+		}
+
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			if err := flush(); err != nil {
+				return err
+			}
+		case txs, ok := <-tc:
+			if !ok {
+				return flush()
+			}
+
+			if buf.add(txs) >= a.getBatchSize() {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (a Adapter) getBatchSize() int {
+	if a.batchSize <= 0 {
+		return DefaultBatchSize
+	}
+
+	return a.batchSize
+}
+
+// Flush saves the transactions that are waiting to be saved by the SaveStream calls of the adapter.
+// It doesn't wait for the batch to be full or for the flush interval to elapse,
+// which allows saving the pending transactions before shutting down.
+// When the write buffer is enabled it also waits until the buffered transactions
+// are saved and returns the errors of the background saves.
+// Transactions received by the streams of adapters created with With are not saved,
+// and Flush does nothing when there are no transactions waiting to be saved.
+func (a Adapter) Flush(ctx context.Context) error {
+	if a.writes != nil {
+		if err := a.writes.flush(ctx); err != nil {
+			return err
+		}
+	}
+
+	if a.buffer == nil {
+		return nil
+	}
+
+	return a.flushBatch(ctx, a.buffer)
+}
+
+// flushBatch saves the transactions of a buffer and calls the batch committed function.
+func (a Adapter) flushBatch(ctx context.Context, buf *txBuffer) error {
+	txs, err := buf.flush(ctx, a.saveCommitted)
+	if err != nil || len(txs) == 0 || a.batchCommitted == nil {
+		return err
+	}
+
+	if err := a.batchCommitted(maxHeight(txs)); err != nil {
+		return fmt.Errorf("batch committed function failed: %w", err)
+	}
+
+	return nil
+}
+
+// saveCommitted saves a list of transactions and returns the number of committed transactions.
+// Transactions are committed in more than one database transaction when they exceed the maximum
+// batch size in bytes, so some of them might be committed when the save fails.
+func (a Adapter) saveCommitted(ctx context.Context, txs []cosmosclient.TX) (int, error) {
+	r, err := a.SaveWithResult(ctx, txs)
+
+	// The skipped transactions are part of the committed batches
+	return len(r.TXIDs) + len(r.Errors), err
+}
+
+// maxHeight returns the maximum block height of a list of transactions.
+func maxHeight(txs []cosmosclient.TX) (height int64) {
+	for _, tx := range txs {
+		if tx.Raw != nil && tx.Raw.Height > height {
+			height = tx.Raw.Height
+		}
+	}
+
+	return height
+}
+
+// txBuffer keeps the transactions that are waiting to be saved.
+type txBuffer struct {
+	mu  sync.Mutex
+	txs []cosmosclient.TX
+}
+
+// add adds transactions to the buffer and returns the number of buffered transactions.
+func (b *txBuffer) add(txs []cosmosclient.TX) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.txs = append(b.txs, txs...)
+
+	return len(b.txs)
+}
+
+// flush saves the buffered transactions and returns the saved transactions.
+// The transactions are taken from the buffer before saving them, so transactions can be
+// added while they are saved. When the save fails the transactions that were not committed
+// are kept in the buffer to be saved again.
+func (b *txBuffer) flush(ctx context.Context, save func(context.Context, []cosmosclient.TX) (int, error)) ([]cosmosclient.TX, error) {
+	b.mu.Lock()
+	txs := b.txs
+	b.txs = nil
+	b.mu.Unlock()
+
+	if len(txs) == 0 {
+		return nil, nil
+	}
+
+	if n, err := save(ctx, txs); err != nil {
+		b.mu.Lock()
+		b.txs = append(append([]cosmosclient.TX{}, txs[n:]...), b.txs...)
+		b.mu.Unlock()
+
+		return nil, err
+	}
+
+	return txs, nil
+}
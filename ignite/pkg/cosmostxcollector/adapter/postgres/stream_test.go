@@ -0,0 +1,276 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+func TestSaveStream(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{saveOptions: saveOptions{batchSize: 1}, db: db}
+	ctx := context.Background()
+	tx := createTestTX(t)
+	tc := make(chan []cosmosclient.TX, 2)
+
+	// Arrange: Each transaction fills a batch so they are saved separately
+	expectSaveTX(mock, true)
+	expectSaveTX(mock, true)
+
+	tc <- []cosmosclient.TX{tx}
+	tc <- []cosmosclient.TX{tx}
+	close(tc)
+
+	// Act
+	err := adapter.SaveStream(ctx, tc)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveStreamWithBatchCommitted(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	var heights []int64
+
+	adapter := Adapter{
+		saveOptions: saveOptions{
+			batchSize: 1,
+		},
+		hookOptions: hookOptions{
+			batchCommitted: func(lastHeight int64) error {
+				heights = append(heights, lastHeight)
+				return nil
+			},
+		},
+		db: db,
+	}
+	ctx := context.Background()
+	tx := createTestTX(t)
+	tc := make(chan []cosmosclient.TX, 2)
+
+	expectSaveTX(mock, true)
+	expectSaveTX(mock, true)
+
+	tc <- []cosmosclient.TX{tx}
+	tc <- []cosmosclient.TX{tx}
+	close(tc)
+
+	// Act
+	err := adapter.SaveStream(ctx, tc)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, []int64{tx.Raw.Height, tx.Raw.Height}, heights)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveStreamWithBatchCommittedError(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	wantErr := errors.New("checkpoint failed")
+	adapter := Adapter{
+		saveOptions: saveOptions{
+			batchSize: 1,
+		},
+		hookOptions: hookOptions{
+			batchCommitted: func(int64) error { return wantErr },
+		},
+		db:     db,
+		buffer: &txBuffer{},
+	}
+	ctx := context.Background()
+	tx := createTestTX(t)
+	tc := make(chan []cosmosclient.TX, 2)
+
+	// Arrange: The stream must stop after the first batch
+	expectSaveTX(mock, true)
+
+	tc <- []cosmosclient.TX{tx}
+	tc <- []cosmosclient.TX{tx}
+	close(tc)
+
+	// Act
+	err := adapter.SaveStream(ctx, tc)
+
+	// Assert
+	require.ErrorIs(t, err, wantErr)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// Assert: The saved batch must not be saved again
+	require.NoError(t, adapter.Flush(ctx))
+}
+
+func TestSaveStreamRemaining(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{saveOptions: saveOptions{batchSize: 10}, db: db}
+	ctx := context.Background()
+	tc := make(chan []cosmosclient.TX, 1)
+
+	// Arrange: The batch is not full so it must be saved when the stream is closed
+	expectSaveTX(mock, true)
+
+	tc <- []cosmosclient.TX{createTestTX(t)}
+	close(tc)
+
+	// Act
+	err := adapter.SaveStream(ctx, tc)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveStreamFlushInterval(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{
+		saveOptions: saveOptions{
+			batchSize:     10,
+			flushInterval: 10 * time.Millisecond,
+		},
+		db: db,
+	}
+	ctx := context.Background()
+	tc := make(chan []cosmosclient.TX)
+	done := make(chan error)
+
+	// Arrange: The batch is not full so it must be saved when the interval elapses
+	expectSaveTX(mock, true)
+
+	// Act
+	go func() {
+		done <- adapter.SaveStream(ctx, tc)
+	}()
+
+	tc <- []cosmosclient.TX{createTestTX(t)}
+
+	// Assert
+	require.Eventually(t, func() bool {
+		return mock.ExpectationsWereMet() == nil
+	}, time.Second, 5*time.Millisecond)
+
+	close(tc)
+	require.NoError(t, <-done)
+}
+
+func TestSaveStreamCanceled(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db}
+	ctx, cancel := context.WithCancel(context.Background())
+	tc := make(chan []cosmosclient.TX)
+
+	cancel()
+
+	// Act
+	err := adapter.SaveStream(ctx, tc)
+
+	// Assert
+	require.ErrorIs(t, err, context.Canceled)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFlush(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db, buffer: &txBuffer{}}
+	ctx := context.Background()
+
+	adapter.buffer.add([]cosmosclient.TX{createTestTX(t)})
+
+	// Arrange: Pending transactions must be saved only once
+	expectSaveTX(mock, true)
+
+	// Act
+	err := adapter.Flush(ctx)
+	require.NoError(t, err)
+
+	err = adapter.Flush(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFlushKeepsUncommittedTXs(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{saveOptions: saveOptions{maxBatchBytes: 1}, db: db, buffer: &txBuffer{}}
+	ctx := context.Background()
+	tx := createTestTX(t)
+
+	adapter.buffer.add([]cosmosclient.TX{tx, tx})
+
+	// Arrange: Each TX is committed separately and the second one fails
+	expectSaveTX(mock, true)
+	mock.ExpectBegin().WillReturnError(errors.New("connection lost"))
+
+	// Arrange: Only the TX that was not committed must be saved again
+	expectSaveTX(mock, true)
+
+	// Act
+	err := adapter.Flush(ctx)
+	require.Error(t, err)
+
+	err = adapter.Flush(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFlushWithDerivedAdapter(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := Adapter{db: db, buffer: &txBuffer{}}
+	clone := mustWith(t, adapter, WithChainID("test-1"))
+	ctx := context.Background()
+
+	adapter.buffer.add([]cosmosclient.TX{createTestTX(t)})
+
+	// Act: The derived adapter must not save the transactions of the original adapter
+	err := clone.Flush(ctx)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// Arrange
+	expectSaveTX(mock, true)
+
+	// Act
+	err = adapter.Flush(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFlushWithoutBuffer(t *testing.T) {
+	require.NoError(t, Adapter{}.Flush(context.Background()))
+}
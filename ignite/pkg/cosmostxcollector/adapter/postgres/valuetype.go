@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// defaultValueColumnType is the type of the attribute value column created by the schemas.
+const defaultValueColumnType = "jsonb"
+
+const (
+	tplAlterAttrValueTypeSQL = `
+		ALTER TABLE attribute ALTER COLUMN value TYPE %s USING %s
+	`
+
+	// tplAttrJSONBValuesSQL selects the attributes with the values converted to JSONB.
+	// It replaces the attribute table in queries that use JSONB operators.
+	tplAttrJSONBValuesSQL = `(
+		SELECT event_id, name, %s AS value, value_type, index, created_at
+		FROM attribute
+	) AS attribute`
+)
+
+// valueColumnTypes contains the supported types for the attribute value column,
+// with the expressions used to convert the values of the default column type.
+var valueColumnTypes = map[string]string{
+	defaultValueColumnType: "value",
+	"json":                 "value::json",
+	"text":                 "value::text",
+	"bytea":                "convert_to(value::text, 'UTF8')",
+}
+
+// jsonbValueColumnTypes contains the expressions used to convert the values of the
+// attribute value column types to JSONB. Values are always saved as JSON so they
+// can be converted to JSONB no matter the type of the column.
+var jsonbValueColumnTypes = map[string]string{
+	"json":  "value::jsonb",
+	"text":  "value::jsonb",
+	"bytea": "convert_from(value, 'UTF8')::jsonb",
+}
+
+// WithValueColumnType configures the type of the attribute value column.
+func WithValueColumnType(t string) Option {
+	return func(a *Adapter) {
+		a.valueColumnType = t
+	}
+}
+
+// validateValueColumnType checks that a type is supported for the attribute value column.
+func validateValueColumnType(t string) error {
+	if _, ok := valueColumnTypes[t]; t != "" && !ok {
+		return fmt.Errorf("%w: value column type '%s' is not supported", ErrInvalidOption, t)
+	}
+
+	return nil
+}
+
+// withValueColumnType changes the script of the schema that creates the attribute
+// table so the value column is created with the type of the value column option.
+// The column type is changed before the script commits the schema transaction,
+// or at the end of the script when it doesn't commit a transaction.
+func (a Adapter) withValueColumnType(script []byte) []byte {
+	if a.valueColumnType == "" || a.valueColumnType == defaultValueColumnType {
+		return script
+	}
+
+	commit := []byte(sqlCommitTX + sqlCommandSuffix)
+	alterSQL := fmt.Sprintf(tplAlterAttrValueTypeSQL, a.valueColumnType, valueColumnTypes[a.valueColumnType])
+
+	b := ScriptBuilder{}
+	if bytes.HasSuffix(script, commit) {
+		b.AppendScript(bytes.TrimSuffix(script, commit))
+		b.AppendCommand(alterSQL)
+		b.CommitTX()
+	} else {
+		b.AppendScript(script)
+		b.AppendCommand(alterSQL)
+	}
+
+	return b.Bytes()
+}
+
+// attrTableSQL returns the SQL to select the attributes in queries that use JSONB operators.
+// The attribute table is used when the value column has the default type.
+func (a Adapter) attrTableSQL() string {
+	expr, ok := jsonbValueColumnTypes[a.valueColumnType]
+	if !ok {
+		return "attribute"
+	}
+
+	return fmt.Sprintf(tplAttrJSONBValuesSQL, expr)
+}
+
+// schemaFiles returns the schema files of the adapter.
+// The schema that creates the attribute table includes the value column type change.
+func (a Adapter) schemaFiles() ([]SchemaFile, error) {
+	files, err := a.schemas.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, f := range files {
+		if f.Version == 1 {
+			files[i].Content = a.withValueColumnType(f.Content)
+		}
+	}
+
+	return files, nil
+}
+
+// DumpSchema returns the SQL scripts of the adapter schema files as a single SQL script.
+// Unlike the DumpSchema function the script includes the value column type option.
+func (a Adapter) DumpSchema() (string, error) {
+	files, err := a.schemaFiles()
+	if err != nil {
+		return "", err
+	}
+
+	return dumpSchemaFiles(files), nil
+}
@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateSchemaWithValueColumnType(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	tplSchemaScript := `BEGIN;
+		INSERT INTO schema(version)
+		VALUES(%d)
+	;%sCOMMIT;`
+
+	fs := fstest.MapFS{
+		"schemas/1.sql": &fstest.MapFile{Data: []byte("/* V1 */")},
+		"schemas/2.sql": &fstest.MapFile{Data: []byte("/* V2 */")},
+	}
+	s := NewSchemas(fs, "")
+	adapter := mustWith(t, Adapter{schemaOptions: schemaOptions{schemas: s}, db: db}, WithValueColumnType("text"))
+
+	// Arrange: Database mock and expectations
+	expectSchemaLock(mock, s)
+	mock.
+		ExpectExec(s.GetTableDDL()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectQuery(s.GetSchemaVersionSQL()).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(uint64(0)))
+
+	// The column type is only changed by the schema that creates the attribute table
+	alterSQL := fmt.Sprintf(tplAlterAttrValueTypeSQL, "text", "value::text") + sqlCommandSuffix
+	mock.
+		ExpectExec(fmt.Sprintf(tplSchemaScript, 1, "/* V1 */"+alterSQL)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectExec(fmt.Sprintf(tplSchemaScript, 2, "/* V2 */")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	expectSchemaUnlock(mock, s)
+
+	// Act
+	err := adapter.UpdateSchema(context.Background(), s)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateSchemaWithValueColumnTypeOtherSchemas(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	tplSchemaScript := `BEGIN;
+		INSERT INTO app_schema(version)
+		VALUES(%d)
+	;%sCOMMIT;`
+
+	fs := fstest.MapFS{
+		"schemas/1.sql": &fstest.MapFile{Data: []byte("/* V1 */")},
+	}
+	s := NewSchemas(fs, "app")
+	adapter := mustWith(t, Adapter{schemaOptions: schemaOptions{schemas: NewSchemas(fs, "")}, db: db}, WithValueColumnType("bytea"))
+
+	// Arrange: Database mock and expectations
+	expectSchemaLock(mock, s)
+	mock.
+		ExpectExec(s.GetTableDDL()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectQuery(s.GetSchemaVersionSQL()).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(uint64(0)))
+	mock.
+		ExpectExec(fmt.Sprintf(tplSchemaScript, 1, "/* V1 */")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	expectSchemaUnlock(mock, s)
+
+	// Act
+	err := adapter.UpdateSchema(context.Background(), s)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithValueColumnTypeDefault(t *testing.T) {
+	// Arrange
+	script := []byte("BEGIN;/* V1 */COMMIT;")
+	adapter := mustWith(t, Adapter{}, WithValueColumnType(defaultValueColumnType))
+
+	// Act
+	got := adapter.withValueColumnType(script)
+
+	// Assert
+	require.Equal(t, script, got)
+}
+
+func TestAggregateAttributeWithValueColumnType(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	adapter := mustWith(t, Adapter{db: db}, WithValueColumnType("bytea"))
+	aggSQL, err := formatAggFunc(AggSum)
+	require.NoError(t, err)
+
+	// Arrange: The values are converted to JSONB to use the JSONB operators
+	attrTable := fmt.Sprintf(tplAttrJSONBValuesSQL, "convert_from(value, 'UTF8')::jsonb")
+	mock.
+		ExpectQuery(fmt.Sprintf(tplAggregateAttrSQL, sqlAttrNumericValue, aggSQL, attrTable)).
+		WithArgs("tx", "fee", int64(1), int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"invalid", "result"}).AddRow(0, 42))
+
+	// Act
+	v, err := adapter.AggregateAttribute(context.Background(), "tx", "fee", AggSum, 1, 10)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.EqualValues(t, 42, v)
+}
+
+func TestAttrTableSQL(t *testing.T) {
+	cases := []struct {
+		name, columnType, want string
+	}{
+		{
+			name: "default",
+			want: "attribute",
+		},
+		{
+			name:       "jsonb",
+			columnType: "jsonb",
+			want:       "attribute",
+		},
+		{
+			name:       "json",
+			columnType: "json",
+			want:       fmt.Sprintf(tplAttrJSONBValuesSQL, "value::jsonb"),
+		},
+		{
+			name:       "text",
+			columnType: "text",
+			want:       fmt.Sprintf(tplAttrJSONBValuesSQL, "value::jsonb"),
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Adapter{schemaOptions: schemaOptions{valueColumnType: tt.columnType}}.attrTableSQL())
+		})
+	}
+}
+
+func TestValidateMigrationsWithValueColumnType(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	fs := fstest.MapFS{
+		"schemas/1.sql": &fstest.MapFile{Data: []byte("/* V1 */")},
+	}
+	s := NewSchemas(fs, "")
+	adapter := mustWith(t, Adapter{schemaOptions: schemaOptions{schemas: s}, db: db}, WithValueColumnType("text"))
+
+	// Arrange: Database mock and expectations for a fresh database
+	expectSchemaLock(mock, s)
+	mock.
+		ExpectQuery(sqlSelectTableExists).
+		WithArgs(s.tableName).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectBegin()
+	mock.
+		ExpectExec("/* V1 */" + fmt.Sprintf(tplAlterAttrValueTypeSQL, "text", "value::text") + sqlCommandSuffix).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	expectSchemaUnlock(mock, s)
+
+	// Act
+	err := adapter.ValidateMigrations(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAdapterDumpSchema(t *testing.T) {
+	// Arrange
+	adapter := mustWith(t, Adapter{schemaOptions: schemaOptions{schemas: NewSchemas(fsSchemas, "")}}, WithValueColumnType("text"))
+
+	// Act
+	dump, err := adapter.DumpSchema()
+
+	// Assert
+	require.NoError(t, err)
+	require.Contains(t, dump, fmt.Sprintf(tplAlterAttrValueTypeSQL, "text", "value::text"))
+}
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+// WithWriteBuffer configures Save to buffer the transactions and save them in the background.
+func WithWriteBuffer(capacity int) Option {
+	return func(a *Adapter) {
+		a.writeBufferCap = capacity
+	}
+}
+
+// writeBuffer keeps the transactions waiting to be saved by a background goroutine.
+type writeBuffer struct {
+	queue   chan cosmosclient.TX
+	flushes chan chan error
+	closing chan struct{}
+	done    chan struct{}
+
+	// The lock allows closing the queue when there are no pending writes
+	mu     sync.RWMutex
+	closed bool
+	once   sync.Once
+
+	errMu sync.Mutex
+	err   error
+}
+
+func newWriteBuffer(capacity int) *writeBuffer {
+	return &writeBuffer{
+		queue:   make(chan cosmosclient.TX, capacity),
+		flushes: make(chan chan error),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// add adds transactions to the buffer and blocks while the buffer is full.
+func (b *writeBuffer) add(ctx context.Context, txs []cosmosclient.TX) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	for _, tx := range txs {
+		select {
+		case b.queue <- tx:
+		case <-b.closing:
+			return ErrClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// flush waits until the buffered transactions are saved.
+// It returns the error of the first background save that failed since the previous flush.
+func (b *writeBuffer) flush(ctx context.Context) error {
+	reply := make(chan error, 1)
+
+	select {
+	case b.flushes <- reply:
+	case <-b.done:
+		return b.takeErr()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close saves the buffered transactions and stops the background goroutine.
+func (b *writeBuffer) close() error {
+	b.once.Do(func() {
+		// Unblock the writes waiting for room in the buffer and wait for them to finish
+		close(b.closing)
+
+		b.mu.Lock()
+		b.closed = true
+		b.mu.Unlock()
+
+		close(b.queue)
+	})
+
+	<-b.done
+
+	return b.takeErr()
+}
+
+// run saves the buffered transactions until the buffer is closed.
+// Transactions are saved as soon as the buffer is empty or when the batch size is reached.
+func (b *writeBuffer) run(save func(context.Context, []cosmosclient.TX) error, batchSize int) {
+	defer close(b.done)
+
+	var batch []cosmosclient.TX
+
+	saveBatch := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		b.setErr(save(context.Background(), batch))
+		batch = nil
+	}
+
+	for {
+		select {
+		case tx, ok := <-b.queue:
+			if !ok {
+				saveBatch()
+				return
+			}
+
+			batch = append(batch, tx)
+			if len(batch) >= batchSize || len(b.queue) == 0 {
+				saveBatch()
+			}
+		case reply := <-b.flushes:
+			// Only this goroutine reads from the queue so the buffered transactions can't be taken
+			for len(b.queue) > 0 {
+				batch = append(batch, <-b.queue)
+				if len(batch) >= batchSize {
+					saveBatch()
+				}
+			}
+
+			saveBatch()
+			reply <- b.takeErr()
+		}
+	}
+}
+
+// setErr keeps an error of the background saves.
+// Only the first error is kept until the errors are returned by a flush.
+func (b *writeBuffer) setErr(err error) {
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// takeErr returns the error of the background saves and clears it.
+func (b *writeBuffer) takeErr() error {
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+
+	err := b.err
+	b.err = nil
+
+	return err
+}
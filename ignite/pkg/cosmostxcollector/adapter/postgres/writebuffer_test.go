@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/ignite/cli/ignite/pkg/cosmosclient"
+)
+
+func TestWriteBufferClose(t *testing.T) {
+	// Arrange
+	var saved []cosmosclient.TX
+
+	b := newWriteBuffer(10)
+	txs := createWriteBufferTXs(3)
+
+	go b.run(func(_ context.Context, txs []cosmosclient.TX) error {
+		saved = append(saved, txs...)
+		return nil
+	}, DefaultBatchSize)
+
+	// Act
+	err := b.add(context.Background(), txs)
+	require.NoError(t, err)
+
+	err = b.close()
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, txs, saved)
+	require.ErrorIs(t, b.add(context.Background(), txs), ErrClosed)
+}
+
+func TestWriteBufferFull(t *testing.T) {
+	// Arrange
+	var saved []cosmosclient.TX
+
+	b := newWriteBuffer(1)
+	txs := createWriteBufferTXs(2)
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+
+	go b.run(func(_ context.Context, txs []cosmosclient.TX) error {
+		// Block the first save so the buffer fills up
+		if len(saved) == 0 {
+			close(started)
+			<-unblock
+		}
+
+		saved = append(saved, txs...)
+		return nil
+	}, DefaultBatchSize)
+
+	require.NoError(t, b.add(context.Background(), txs[:1]))
+	<-started
+	require.NoError(t, b.add(context.Background(), txs[1:]))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Act
+	err := b.add(ctx, createWriteBufferTXs(1))
+
+	// Assert
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(unblock)
+	require.NoError(t, b.close())
+	require.Equal(t, txs, saved)
+}
+
+func TestWriteBufferFlush(t *testing.T) {
+	// Arrange
+	wantErr := errors.New("save failed")
+	b := newWriteBuffer(10)
+
+	go b.run(func(context.Context, []cosmosclient.TX) error {
+		return wantErr
+	}, DefaultBatchSize)
+
+	require.NoError(t, b.add(context.Background(), createWriteBufferTXs(1)))
+
+	// Act
+	err := b.flush(context.Background())
+
+	// Assert
+	require.ErrorIs(t, err, wantErr)
+	require.NoError(t, b.flush(context.Background()))
+	require.NoError(t, b.close())
+}
+
+func TestWriteBufferFlushCanceled(t *testing.T) {
+	// Arrange
+	b := newWriteBuffer(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act: The buffer doesn't run so the flush waits until the context is done
+	err := b.flush(ctx)
+
+	// Assert
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWriteBufferWithDerivedAdapter(t *testing.T) {
+	// Arrange
+	db, mock := createMatchEqualSQLMock(t)
+	defer db.Close()
+
+	var (
+		buffered  []cosmosclient.TX
+		committed []cosmosclient.TX
+	)
+
+	adapter := Adapter{db: db, writes: newWriteBuffer(10)}
+	txs := []cosmosclient.TX{createTestTX(t)}
+
+	go adapter.writes.run(func(_ context.Context, txs []cosmosclient.TX) error {
+		buffered = append(buffered, txs...)
+		return nil
+	}, DefaultBatchSize)
+
+	clone := mustWith(t, adapter, WithPreCommit(func(_ context.Context, txs []cosmosclient.TX) error {
+		committed = append(committed, txs...)
+		return nil
+	}))
+
+	// Arrange: The derived adapter must save the transactions using its own options
+	expectSaveTX(mock, true)
+
+	// Act
+	err := clone.Save(context.Background(), txs)
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, clone.Flush(context.Background()))
+	require.NoError(t, adapter.writes.close())
+	require.Equal(t, txs, committed)
+	require.Empty(t, buffered)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// createWriteBufferTXs creates a number of transactions with different heights.
+func createWriteBufferTXs(n int) []cosmosclient.TX {
+	txs := make([]cosmosclient.TX, n)
+	for i := range txs {
+		txs[i] = cosmosclient.TX{Raw: &ctypes.ResultTx{Height: int64(i + 1)}}
+	}
+
+	return txs
+}
@@ -28,6 +28,9 @@ type Attribute struct {
 	value []byte
 
 	Name string
+
+	// ValueType contains the type of the attribute value when the data backend provides it.
+	ValueType string
 }
 
 // Value returns the attribute value.